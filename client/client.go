@@ -1,28 +1,71 @@
 package client
 
 import (
+	"context"
 	"os"
+	"sync"
+	"time"
 
 	consulAPI "github.com/hashicorp/consul/api"
 	"github.com/leads-su/broker"
+	"github.com/leads-su/consul/logging"
+	"github.com/leads-su/consul/metrics"
 	"github.com/leads-su/consul/state"
-	"github.com/leads-su/logger"
+)
+
+const (
+	defaultProbeTimeout = 3 * time.Second
 )
 
 // PingedServer holds information about pinged server
 type PingedServer struct {
 	server *ConnectionInformation
 	rtt    int64
+	probe  ProbeResult
+}
+
+// Server returns information about pinged server
+func (pinged PingedServer) Server() *ConnectionInformation {
+	return pinged.server
+}
+
+// RTT returns round trip time to pinged server in milliseconds
+func (pinged PingedServer) RTT() int64 {
+	return pinged.rtt
+}
+
+// Probe returns result of the probe performed against server
+func (pinged PingedServer) Probe() ProbeResult {
+	return pinged.probe
 }
 
 // Client represents structure of client
 type Client struct {
+	mutex     sync.RWMutex
 	broker    *broker.Broker
 	channel   chan interface{}
 	servers   []*ConnectionInformation
 	server    *ConnectionInformation
 	apiClient *consulAPI.Client
 	apiConfig *consulAPI.Config
+
+	strategy     SelectionStrategy
+	probeTimeout time.Duration
+	probeAgent   bool
+
+	failoverDisabled  bool
+	failoverInterval  time.Duration
+	failoverThreshold int
+	monitorQuit       chan struct{}
+	monitorDone       chan struct{}
+	supervisor        *SupervisorOptions
+	registrants       []Registrant
+	metrics           *metrics.Metrics
+	logger            logging.Logger
+
+	stateMutex sync.Mutex
+	state      state.State
+	changed    chan struct{}
 }
 
 // WithCustomBroker initialize client with custom broker
@@ -56,9 +99,11 @@ func SingleServer(connection *ConnectionInformation) *Client {
 			connection,
 		},
 		server: connection,
+		state:  state.ConsulConfigured,
 	}
 	client.broker, client.channel = client.initializeBroker()
 	client.Broker().Publish(state.ConsulConfigurationPending)
+	client.Broker().Publish(state.ConsulConfigured)
 	return client
 }
 
@@ -78,7 +123,9 @@ func (client *Client) WithAccessToken(accessToken string) *Client {
 	for _, server := range client.servers {
 		server.accessToken = accessToken
 	}
-	client.server.accessToken = accessToken
+	if client.server != nil {
+		client.server.accessToken = accessToken
+	}
 	return client
 }
 
@@ -87,54 +134,145 @@ func (client *Client) WithDataCenter(dataCenter string) *Client {
 	for _, server := range client.servers {
 		server.dataCenter = dataCenter
 	}
-	client.server.dataCenter = dataCenter
+	if client.server != nil {
+		client.server.dataCenter = dataCenter
+	}
+	return client
+}
+
+// WithTLS sets TLS configuration for all servers
+func (client *Client) WithTLS(tls *TLS) *Client {
+	for _, server := range client.servers {
+		server.SetTLS(tls)
+	}
+	if client.server != nil {
+		client.server.SetTLS(tls)
+	}
 	return client
 }
 
 // Connect connect to best (available) Consul server
 func (client *Client) Connect() *Client {
+	if err := client.ConnectE(); err != nil {
+		client.Logger().Errorf("consul:client", "%s", err.Error())
+		os.Exit(1)
+	}
+	return client
+}
+
+// ConnectE connect to best (available) Consul server, or returns error
+func (client *Client) ConnectE() error {
+	if client.State() == state.ConsulConfigurationPending {
+		return ErrNoServerSelected
+	}
+	if err := client.transition(state.ConsulStarting); err != nil {
+		return err
+	}
 	client.Broker().Publish(state.ConsulStarting)
+	client.mutex.Lock()
+	if client.server == nil {
+		client.mutex.Unlock()
+		client.transition(state.ConsulConfigured)
+		return ErrNoServerSelected
+	}
 	client.configureAPIClient()
 	apiClient, err := consulAPI.NewClient(client.apiConfig)
 	if err != nil {
-		logger.Fatalf(
-			"consul:client",
-			"failed to initialize connection to %s (datacenter: %s)",
-			client.server.HostPort(),
-			client.server.DataCenter(),
-		)
-		return nil
-	}
-	logger.Infof(
+		client.mutex.Unlock()
+		client.transition(state.ConsulConfigured)
+		return &ConnectionError{
+			Server:     client.server.HostPort(),
+			DataCenter: client.server.DataCenter(),
+			Err:        err,
+		}
+	}
+	client.Logger().With("server", client.server.HostPort()).Infof(
 		"consul:client",
 		"connecting to %s (datacenter: %s)",
 		client.server.HostPort(),
 		client.server.DataCenter(),
 	)
 	client.apiClient = apiClient
+	client.mutex.Unlock()
+	client.transition(state.ConsulStarted)
 	client.Broker().Publish(state.ConsulStarted)
-	return client
+	client.startMonitor()
+	return nil
+}
+
+// ConnectContext connect to best (available) Consul server and disconnects once context is cancelled
+func (client *Client) ConnectContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := client.ConnectE(); err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		client.DisconnectE()
+	}()
+	return nil
 }
 
 // Disconnect disconnects client from Consul server
 func (client *Client) Disconnect() *Client {
+	if err := client.DisconnectE(); err != nil {
+		client.Logger().Warnf("consul:client", "%s", err.Error())
+	}
+	return client
+}
+
+// DisconnectE disconnects client from Consul server, or returns TransitionError if client is not connected
+func (client *Client) DisconnectE() error {
+	if err := client.transition(state.ConsulShuttingDown); err != nil {
+		return err
+	}
+	client.stopMonitor()
 	client.Broker().Publish(state.ConsulShuttingDown)
+	client.mutex.Lock()
 	client.server = nil
 	client.apiClient = nil
 	client.apiConfig = nil
-	return client
+	client.mutex.Unlock()
+	client.transition(state.ConsulConfigurationPending)
+	client.Broker().Publish(state.ConsulConfigurationPending)
+	return nil
 }
 
 // APIClient returns Consul API client
 func (client *Client) APIClient() *consulAPI.Client {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
 	return client.apiClient
 }
 
+// APIClientE returns Consul API client or ErrNotConnected if client is not connected to any server
+func (client *Client) APIClientE() (*consulAPI.Client, error) {
+	apiClient := client.APIClient()
+	if apiClient == nil {
+		return nil, ErrNotConnected
+	}
+	return apiClient, nil
+}
+
+// Server returns information about server client is currently connected to
+func (client *Client) Server() *ConnectionInformation {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	return client.server
+}
+
 // Broker returns instance of Broker
 func (client *Client) Broker() *broker.Broker {
 	return client.broker
 }
 
+// Subscribe returns typed subscription to events of the client, delivering only events of given states (or all events if no state is specified)
+func (client *Client) Subscribe(states ...state.State) *state.Subscription {
+	return state.Subscribe(client.Broker(), states...)
+}
+
 // Channel returns Broker channel for package
 func (client *Client) Channel() chan interface{} {
 	return client.channel
@@ -145,57 +283,186 @@ func (client *Client) IsSingleServer() bool {
 	return len(client.servers) == 1
 }
 
-// SelectBestServer selects best server to connect to (simple and dumb, the first one available)
+// SelectBestServer selects best server to connect to using configured selection strategy (lowest latency by default)
 func (client *Client) SelectBestServer() *Client {
-	var pingedServers []PingedServer
-	var bestServer *ConnectionInformation = nil
-	var previousPing int64 = 999
+	if err := client.SelectBestServerE(); err != nil {
+		client.Logger().Errorf("consul:client", "%s", err.Error())
+		os.Exit(1)
+	}
+	return client
+}
 
-	for _, server := range client.servers {
-		available, rtt := server.IsAvailableWithRoundTrip()
-		if available {
-			pingedServers = append(pingedServers, PingedServer{
-				server: server,
-				rtt:    rtt,
-			})
-		} else {
-			logger.Warnf("consul:client", "server %s is not available for connection", server.HostPort())
-		}
+// SelectBestServerE selects best server to connect to, or returns ErrNoServersAvailable
+func (client *Client) SelectBestServerE() error {
+	if current := client.State(); current != state.ConsulConfigurationPending && current != state.ConsulConfigured {
+		return &TransitionError{From: current, To: state.ConsulConfigured}
 	}
 
-	serversCount := len(pingedServers)
+	bestServer, rtt := client.bestServer()
 
-	if serversCount == 0 {
-		logger.Fatalf("consul:client", "there are no alive consul servers available to connect to")
-		os.Exit(1)
+	if bestServer == nil {
+		return ErrNoServersAvailable
 	}
 
-	for _, entity := range pingedServers {
-		if entity.rtt < previousPing {
-			bestServer = entity.server
-			previousPing = entity.rtt
-		}
+	if err := client.transition(state.ConsulConfigured); err != nil {
+		return err
 	}
 
-	logger.Infof("consul:client", "selecting %s as a target server with ping of %dms", bestServer.HostPort(), previousPing)
+	client.Logger().With("server", bestServer.HostPort()).Infof("consul:client", "selecting %s as a target server with ping of %dms", bestServer.HostPort(), rtt)
 
+	client.mutex.Lock()
 	client.server = bestServer
-	client.Broker().Publish(state.ConsulConfigured)
+	client.mutex.Unlock()
+	client.Metrics().ServerSelected(bestServer.HostPort())
+	state.Publish(client.Broker(), state.ServerSelected{
+		Server:     bestServer.HostPort(),
+		DataCenter: bestServer.DataCenter(),
+		RTT:        time.Duration(rtt) * time.Millisecond,
+	})
+	return nil
+}
+
+// bestServer probes all servers in parallel and returns the one chosen by selection strategy (or nil if none is available)
+func (client *Client) bestServer() (*ConnectionInformation, int64) {
+	pingedServers := client.pingServers()
+	if len(pingedServers) == 0 {
+		return nil, -1
+	}
+
+	strategy := client.strategy
+	if strategy == nil {
+		strategy = NewLeaderReachableStrategy(NewLowestLatencyStrategy())
+	}
+
+	bestServer := strategy.Select(pingedServers)
+	if bestServer == nil {
+		return nil, -1
+	}
+	return bestServer.server, bestServer.rtt
+}
+
+// pingServers probes all servers in parallel and returns reachable ones (including servers without cluster leader) in configuration order
+func (client *Client) pingServers() []PingedServer {
+	timeout := client.probeTimeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := make([]ProbeResult, len(client.servers))
+	var group sync.WaitGroup
+	for index, server := range client.servers {
+		group.Add(1)
+		go func(index int, server *ConnectionInformation) {
+			defer group.Done()
+			if client.probeAgent {
+				results[index] = server.ProbeWithAgent(ctx)
+			} else {
+				results[index] = server.Probe(ctx)
+			}
+		}(index, server)
+	}
+	group.Wait()
+
+	var pingedServers []PingedServer
+	for index, server := range client.servers {
+		result := results[index]
+		if result.Reachable {
+			client.Metrics().ObserveProbe(server.HostPort(), result.Latency)
+		}
+		if result.AgentError != nil {
+			client.Logger().With("server", server.HostPort()).Warnf("consul:client", "unable to retrieve agent information from server %s - %s", server.HostPort(), result.AgentError.Error())
+		}
+		if result.Error != nil {
+			client.Logger().With("server", server.HostPort()).Warnf("consul:client", "server %s is not available for connection - %s", server.HostPort(), result.Error.Error())
+			continue
+		}
+		if !result.HasLeader() {
+			client.Logger().With("server", server.HostPort()).Warnf("consul:client", "server %s reports that cluster has no leader", server.HostPort())
+		}
+		pingedServers = append(pingedServers, PingedServer{
+			server: server,
+			rtt:    result.Latency.Milliseconds(),
+			probe:  result,
+		})
+	}
+	return pingedServers
+}
+
+// WithSelectionStrategy sets strategy used to select server to connect to, strategy receives reachable servers
+// even if they report no cluster leader, wrap it into LeaderReachableStrategy to prefer servers with leader
+func (client *Client) WithSelectionStrategy(strategy SelectionStrategy) *Client {
+	client.strategy = strategy
+	return client
+}
+
+// WithAgentProbe enables querying of '/v1/agent/self' during server selection to retrieve agent version
+func (client *Client) WithAgentProbe() *Client {
+	client.probeAgent = true
+	return client
+}
+
+// WithLogger sets logger used by the client and components created with it
+func (client *Client) WithLogger(logger logging.Logger) *Client {
+	client.logger = logger
+	return client
+}
+
+// Logger returns logger of the client, or default logger if it is not specified
+func (client *Client) Logger() logging.Logger {
+	return logging.Or(client.logger)
+}
+
+// WithMetrics enables collection of metrics by the client and components created with it
+func (client *Client) WithMetrics(instance *metrics.Metrics) *Client {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.metrics = instance
+	return client
+}
+
+// Metrics returns metrics of the client, or nil if metrics are not enabled
+func (client *Client) Metrics() *metrics.Metrics {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	return client.metrics
+}
+
+// WithProbeTimeout sets how long server selection and server monitoring wait for servers to respond
+func (client *Client) WithProbeTimeout(timeout time.Duration) *Client {
+	client.probeTimeout = timeout
+	for _, server := range client.servers {
+		server.probeTimeout = timeout
+	}
+	if client.server != nil {
+		client.server.probeTimeout = timeout
+	}
 	return client
 }
 
 // configureAPIClient configures Consul API client
 func (client *Client) configureAPIClient() {
+	client.apiConfig = apiConfiguration(client.server)
+}
+
+// apiConfiguration builds Consul API client configuration for specified server
+func apiConfiguration(server *ConnectionInformation) *consulAPI.Config {
 	clientConfiguration := consulAPI.DefaultConfig()
-	clientConfiguration.Scheme = client.server.Scheme()
-	clientConfiguration.Address = client.server.HostPort()
-	clientConfiguration.Datacenter = client.server.DataCenter()
+	clientConfiguration.Scheme = server.Scheme()
+	clientConfiguration.Address = server.HostPort()
+	clientConfiguration.Datacenter = server.DataCenter()
+
+	if server.UsesAccessToken() {
+		clientConfiguration.Token = server.AccessToken()
+	}
 
-	if client.server.UsesAccessToken() {
-		clientConfiguration.Token = client.server.AccessToken()
+	if server.UsesTLS() {
+		clientConfiguration.TLSConfig = server.APITLSConfig()
 	}
 
-	client.apiConfig = clientConfiguration
+	return clientConfiguration
 }
 
 // initializeBroker creates new instance of messages broker
@@ -1,20 +1,25 @@
 package client
 
 import (
+	"context"
 	"fmt"
-	"net"
 	"net/http"
-	"net/http/httptrace"
 	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
 )
 
 // ConnectionInformation represents structure of connection information object
 type ConnectionInformation struct {
-	scheme      string
-	host        string
-	port        uint
-	dataCenter  string
-	accessToken string
+	scheme       string
+	host         string
+	port         uint
+	dataCenter   string
+	accessToken  string
+	tls          *TLS
+	weight       uint
+	priority     int
+	probeTimeout time.Duration // Limits availability checks, set by client through WithProbeTimeout
 }
 
 // Connection represents structure of connection object
@@ -24,13 +29,28 @@ type Connection struct {
 	Port        uint
 	DataCenter  string
 	AccessToken string
+	TLS         *TLS
+	Weight      uint // Used by weighted selection strategy | Defaults to 1
+	Priority    int  // Used by priority selection strategy, lower value wins | Defaults to 0
+}
+
+// TLS represents structure of TLS configuration for connection
+type TLS struct {
+	CAFile             string // Path to CA certificate file
+	CAPem              []byte // CA certificate in PEM format (used instead of CAFile)
+	CertFile           string // Path to client certificate file (for mTLS)
+	KeyFile            string // Path to client key file (for mTLS)
+	ServerName         string // Server name used for certificate verification
+	InsecureSkipVerify bool   // Disables verification of server certificate
 }
 
 // NewConnection returns instance of new connection
 func NewConnection(connection *Connection) *ConnectionInformation {
 	information := &ConnectionInformation{}
 
-	if connection.Scheme == "" {
+	if connection.Scheme == "" && connection.TLS != nil {
+		information.scheme = "https"
+	} else if connection.Scheme == "" {
 		information.scheme = "http"
 	} else {
 		information.scheme = connection.Scheme
@@ -60,6 +80,16 @@ func NewConnection(connection *Connection) *ConnectionInformation {
 		information.accessToken = connection.AccessToken
 	}
 
+	information.tls = connection.TLS
+
+	if connection.Weight == 0 {
+		information.weight = 1
+	} else {
+		information.weight = connection.Weight
+	}
+
+	information.priority = connection.Priority
+
 	return information
 }
 
@@ -71,6 +101,7 @@ func NewEmptyConnection() *ConnectionInformation {
 		port:        8500,
 		dataCenter:  "dc0",
 		accessToken: "",
+		weight:      1,
 	}
 }
 
@@ -134,6 +165,62 @@ func (information *ConnectionInformation) UsesAccessToken() bool {
 	return information.AccessToken() != ""
 }
 
+// TLS returns TLS configuration for connection
+func (information *ConnectionInformation) TLS() *TLS {
+	return information.tls
+}
+
+// SetTLS sets connection TLS configuration, `http` scheme is upgraded to `https` when TLS is specified
+func (information *ConnectionInformation) SetTLS(tls *TLS) *ConnectionInformation {
+	information.tls = tls
+	if tls != nil && information.scheme == "http" {
+		information.scheme = "https"
+	}
+	return information
+}
+
+// UsesTLS indicates whether TLS configuration is defined for the connection
+func (information *ConnectionInformation) UsesTLS() bool {
+	return information.TLS() != nil
+}
+
+// APITLSConfig returns TLS configuration in format expected by Consul API client
+func (information *ConnectionInformation) APITLSConfig() consulAPI.TLSConfig {
+	if !information.UsesTLS() {
+		return consulAPI.TLSConfig{}
+	}
+	return consulAPI.TLSConfig{
+		Address:            information.tls.ServerName,
+		CAFile:             information.tls.CAFile,
+		CAPem:              information.tls.CAPem,
+		CertFile:           information.tls.CertFile,
+		KeyFile:            information.tls.KeyFile,
+		InsecureSkipVerify: information.tls.InsecureSkipVerify,
+	}
+}
+
+// Weight returns weight for connection
+func (information *ConnectionInformation) Weight() uint {
+	return information.weight
+}
+
+// SetWeight sets connection weight
+func (information *ConnectionInformation) SetWeight(weight uint) *ConnectionInformation {
+	information.weight = weight
+	return information
+}
+
+// Priority returns priority for connection
+func (information *ConnectionInformation) Priority() int {
+	return information.priority
+}
+
+// SetPriority sets connection priority
+func (information *ConnectionInformation) SetPriority(priority int) *ConnectionInformation {
+	information.priority = priority
+	return information
+}
+
 // HostPort returns host:port string for connection
 func (information *ConnectionInformation) HostPort() string {
 	return fmt.Sprintf("%s:%d", information.Host(), information.Port())
@@ -144,48 +231,50 @@ func (information *ConnectionInformation) FullPath() string {
 	return fmt.Sprintf("%s://%s", information.Scheme(), information.HostPort())
 }
 
-// IsAvailable checks if specified connection is available for use
+// IsAvailable checks if specified connection is available for use (server responds and has cluster leader)
 func (information *ConnectionInformation) IsAvailable() bool {
-	timeout := time.Second * 3
-	connector, err := net.DialTimeout("tcp", information.HostPort(), timeout)
-	if err != nil {
-		return false
-	}
+	ctx, cancel := information.probeContext()
+	defer cancel()
+	return information.Probe(ctx).Available()
+}
 
-	if connector != nil {
-		defer connector.Close()
-		return true
+// probeContext creates context limited by probe timeout of the connection
+func (information *ConnectionInformation) probeContext() (context.Context, context.CancelFunc) {
+	timeout := information.probeTimeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
 	}
+	return context.WithTimeout(context.Background(), timeout)
+}
 
-	return false
+// transport creates HTTP transport configured with connection TLS settings
+func (information *ConnectionInformation) transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if information.UsesTLS() {
+		tlsConfiguration := information.APITLSConfig()
+		tlsClientConfig, err := consulAPI.SetupTLSConfig(&tlsConfiguration)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsClientConfig
+	}
+	return transport, nil
 }
 
 // RoundTrip returns "ping" value between client and server
 func (information *ConnectionInformation) RoundTrip() int64 {
-	request, _ := http.NewRequest("GET", information.FullPath(), nil)
-	var connectStart, dnsStart time.Time
-	var connectEnd, dnsEnd time.Duration
-
-	trace := &httptrace.ClientTrace{
-		DNSStart: func(dsi httptrace.DNSStartInfo) {
-			dnsStart = time.Now()
-		},
-		DNSDone: func(ddi httptrace.DNSDoneInfo) {
-			dnsEnd = time.Since(dnsStart)
-		},
-		ConnectStart: func(network, addr string) {
-			connectStart = time.Now()
-		},
-		ConnectDone: func(network, addr string, err error) {
-			connectEnd = time.Since(connectStart)
-		},
-	}
+	ctx, cancel := information.probeContext()
+	defer cancel()
+	return information.RoundTripContext(ctx)
+}
 
-	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
-	if _, err := http.DefaultTransport.RoundTrip(request); err != nil {
+// RoundTripContext returns "ping" value between client and server, giving up once context is done
+func (information *ConnectionInformation) RoundTripContext(ctx context.Context) int64 {
+	result := information.Probe(ctx)
+	if !result.Available() {
 		return -1
 	}
-	return (connectEnd + dnsEnd).Milliseconds()
+	return result.Latency.Milliseconds()
 }
 
 // IsAvailableWithRoundTrip checks whether server is available and returns round trip time
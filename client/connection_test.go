@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetTLS(t *testing.T) {
+	tests := []struct {
+		name       string
+		connection *ConnectionInformation
+		tls        *TLS
+		want       string
+	}{
+		{name: "default scheme is upgraded", connection: NewEmptyConnection(), tls: &TLS{}, want: "https"},
+		{name: "https scheme is kept", connection: NewEmptyConnection().SetScheme("https"), tls: &TLS{}, want: "https"},
+		{name: "scheme is kept without tls", connection: NewEmptyConnection(), want: "http"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			connection := test.connection.SetTLS(test.tls)
+			if connection.Scheme() != test.want {
+				t.Errorf("Scheme() = %q, want %q", connection.Scheme(), test.want)
+			}
+			if connection.TLS() != test.tls {
+				t.Errorf("TLS() = %+v, want %+v", connection.TLS(), test.tls)
+			}
+		})
+	}
+}
+
+func TestWithTLS(t *testing.T) {
+	tls := &TLS{CAFile: "/etc/consul/ca.pem"}
+	server := NewConnection(&Connection{Host: "first"})
+	client := &Client{
+		servers: []*ConnectionInformation{server, NewConnection(&Connection{Host: "second", Scheme: "https"})},
+		server:  server,
+	}
+
+	client.WithTLS(tls)
+	for _, connection := range client.servers {
+		if connection.FullPath() != "https://"+connection.HostPort() || connection.TLS() != tls {
+			t.Errorf("connection %s uses TLS %+v, want https with %+v", connection.FullPath(), connection.TLS(), tls)
+		}
+	}
+}
+
+func TestWithProbeTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		json.NewEncoder(writer).Encode("10.0.0.1:8300")
+	}))
+	defer server.Close()
+	connection := connectionTo(t, server, "")
+	client := &Client{servers: []*ConnectionInformation{connection}}
+
+	if !connection.IsAvailable() {
+		t.Fatal("IsAvailable() = false within default probe timeout")
+	}
+
+	client.WithProbeTimeout(50 * time.Millisecond)
+	if connection.IsAvailable() {
+		t.Error("IsAvailable() = true for server responding after probe timeout")
+	}
+	if roundTrip := connection.RoundTrip(); roundTrip != -1 {
+		t.Errorf("RoundTrip() = %d for server responding after probe timeout, want -1", roundTrip)
+	}
+}
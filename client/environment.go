@@ -0,0 +1,135 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+const (
+	// HTTPAddressesEnvName defines comma-separated list of servers (takes precedence over CONSUL_HTTP_ADDR)
+	HTTPAddressesEnvName = "CONSUL_HTTP_ADDRS"
+	// DataCenterEnvName defines datacenter used for all servers
+	DataCenterEnvName = "CONSUL_DATACENTER"
+)
+
+// FromEnvironment creates client from standard Consul environment variables
+func FromEnvironment() (*Client, error) {
+	connections, err := ConnectionsFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return MultipleServers(connections), nil
+}
+
+// ConnectionsFromEnvironment creates list of connections from standard Consul environment variables
+func ConnectionsFromEnvironment() ([]*ConnectionInformation, error) {
+	addresses := os.Getenv(HTTPAddressesEnvName)
+	if addresses == "" {
+		addresses = os.Getenv(consulAPI.HTTPAddrEnvName)
+	}
+	if addresses == "" {
+		addresses = "localhost:8500"
+	}
+
+	template := Connection{
+		DataCenter:  os.Getenv(DataCenterEnvName),
+		AccessToken: os.Getenv(consulAPI.HTTPTokenEnvName),
+	}
+
+	if template.AccessToken == "" {
+		if tokenFile := os.Getenv(consulAPI.HTTPTokenFileEnvName); tokenFile != "" {
+			token, err := readTokenFile(tokenFile)
+			if err != nil {
+				return nil, err
+			}
+			template.AccessToken = token
+		}
+	}
+
+	useSSL := false
+	if value := os.Getenv(consulAPI.HTTPSSLEnvName); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s - %w", consulAPI.HTTPSSLEnvName, err)
+		}
+		useSSL = enabled
+	}
+
+	tls := &TLS{
+		CAFile:     os.Getenv(consulAPI.HTTPCAFile),
+		CertFile:   os.Getenv(consulAPI.HTTPClientCert),
+		KeyFile:    os.Getenv(consulAPI.HTTPClientKey),
+		ServerName: os.Getenv(consulAPI.HTTPTLSServerName),
+	}
+	if value := os.Getenv(consulAPI.HTTPSSLVerifyEnvName); value != "" {
+		verify, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s - %w", consulAPI.HTTPSSLVerifyEnvName, err)
+		}
+		tls.InsecureSkipVerify = !verify
+	}
+	if tls.CAFile != "" || tls.CertFile != "" || tls.KeyFile != "" || tls.ServerName != "" || tls.InsecureSkipVerify {
+		template.TLS = tls
+	}
+
+	var connections []*ConnectionInformation
+	for _, address := range strings.Split(addresses, ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+		connection := template
+		if err := parseAddress(address, useSSL, &connection); err != nil {
+			return nil, err
+		}
+		// Datacenter is left empty when it is not specified, so agent's own datacenter is used instead of "dc0"
+		connections = append(connections, NewConnection(&connection).SetDataCenter(connection.DataCenter))
+	}
+
+	if len(connections) == 0 {
+		return nil, fmt.Errorf("no consul servers defined in %s", HTTPAddressesEnvName)
+	}
+	return connections, nil
+}
+
+// parseAddress parses address in "[scheme://]host[:port]" format into connection
+func parseAddress(address string, useSSL bool, connection *Connection) error {
+	connection.Scheme = "http"
+	if useSSL {
+		connection.Scheme = "https"
+	}
+
+	if index := strings.Index(address, "://"); index != -1 {
+		connection.Scheme = address[:index]
+		address = address[index+3:]
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		connection.Host = address
+		return nil
+	}
+
+	parsedPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid port in consul address %s - %w", address, err)
+	}
+	connection.Host = host
+	connection.Port = uint(parsedPort)
+	return nil
+}
+
+// readTokenFile reads access token from file
+func readTokenFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read token file %s - %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
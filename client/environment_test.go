@@ -0,0 +1,118 @@
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestConnectionsFromEnvironment(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "consul.token")
+	if err := ioutil.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("unable to write token file - %s", err)
+	}
+
+	tests := []struct {
+		name        string
+		environment map[string]string
+		want        []string
+		token       string
+		dataCenter  string
+		insecure    bool
+		wantErr     bool
+	}{
+		{
+			name: "default address",
+			want: []string{"http://localhost:8500"},
+		},
+		{
+			name:        "single address",
+			environment: map[string]string{"CONSUL_HTTP_ADDR": "consul.local:8501"},
+			want:        []string{"http://consul.local:8501"},
+		},
+		{
+			name:        "multiple addresses take precedence",
+			environment: map[string]string{"CONSUL_HTTP_ADDR": "ignored:8500", "CONSUL_HTTP_ADDRS": "first:8500, https://second:8501,,third"},
+			want:        []string{"http://first:8500", "https://second:8501", "http://third:8500"},
+		},
+		{
+			name:        "ssl without explicit scheme",
+			environment: map[string]string{"CONSUL_HTTP_ADDR": "consul.local:8501", "CONSUL_HTTP_SSL": "true"},
+			want:        []string{"https://consul.local:8501"},
+		},
+		{
+			name:        "invalid port",
+			environment: map[string]string{"CONSUL_HTTP_ADDR": "consul.local:port"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid ssl flag",
+			environment: map[string]string{"CONSUL_HTTP_SSL": "maybe"},
+			wantErr:     true,
+		},
+		{
+			name:        "skip certificate verification",
+			environment: map[string]string{"CONSUL_HTTP_SSL_VERIFY": "false"},
+			want:        []string{"http://localhost:8500"},
+			insecure:    true,
+		},
+		{
+			name:        "token takes precedence over token file",
+			environment: map[string]string{"CONSUL_HTTP_TOKEN": "token", "CONSUL_HTTP_TOKEN_FILE": tokenFile},
+			want:        []string{"http://localhost:8500"},
+			token:       "token",
+		},
+		{
+			name:        "token from file",
+			environment: map[string]string{"CONSUL_HTTP_TOKEN_FILE": tokenFile},
+			want:        []string{"http://localhost:8500"},
+			token:       "file-token",
+		},
+		{
+			name:        "missing token file",
+			environment: map[string]string{"CONSUL_HTTP_TOKEN_FILE": filepath.Join(t.TempDir(), "missing")},
+			wantErr:     true,
+		},
+		{
+			name:        "datacenter",
+			environment: map[string]string{"CONSUL_DATACENTER": "dc1"},
+			want:        []string{"http://localhost:8500"},
+			dataCenter:  "dc1",
+		},
+		{
+			name:        "datacenter is left empty",
+			environment: map[string]string{"CONSUL_HTTP_ADDR": "consul.local:8500"},
+			want:        []string{"http://consul.local:8500"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"CONSUL_HTTP_ADDR", "CONSUL_HTTP_ADDRS", "CONSUL_HTTP_TOKEN", "CONSUL_HTTP_TOKEN_FILE", "CONSUL_HTTP_SSL", "CONSUL_HTTP_SSL_VERIFY", "CONSUL_CACERT", "CONSUL_CLIENT_CERT", "CONSUL_CLIENT_KEY", "CONSUL_TLS_SERVER_NAME", "CONSUL_DATACENTER"} {
+				t.Setenv(name, test.environment[name])
+			}
+
+			connections, err := ConnectionsFromEnvironment()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ConnectionsFromEnvironment() error = %v, want error %t", err, test.wantErr)
+			}
+			if len(connections) != len(test.want) {
+				t.Fatalf("ConnectionsFromEnvironment() returned %d connections, want %d", len(connections), len(test.want))
+			}
+			for index, connection := range connections {
+				if connection.FullPath() != test.want[index] {
+					t.Errorf("FullPath() = %q, want %q", connection.FullPath(), test.want[index])
+				}
+				if connection.AccessToken() != test.token {
+					t.Errorf("AccessToken() = %q, want %q", connection.AccessToken(), test.token)
+				}
+				if connection.DataCenter() != test.dataCenter {
+					t.Errorf("DataCenter() = %q, want %q", connection.DataCenter(), test.dataCenter)
+				}
+				if insecure := connection.UsesTLS() && connection.TLS().InsecureSkipVerify; insecure != test.insecure {
+					t.Errorf("InsecureSkipVerify = %t, want %t", insecure, test.insecure)
+				}
+			}
+		})
+	}
+}
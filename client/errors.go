@@ -0,0 +1,67 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/leads-su/consul/state"
+)
+
+var (
+	// ErrNoServersAvailable is returned when none of the configured servers responded
+	ErrNoServersAvailable = errors.New("there are no alive consul servers available to connect to")
+	// ErrNoServerSelected is returned when connection is requested before server was selected
+	ErrNoServerSelected = errors.New("there is no consul server selected to connect to")
+	// ErrNotConnected is returned when operation requires client to be connected
+	ErrNotConnected = errors.New("client is not connected to consul")
+	// ErrInvalidTransition is returned (wrapped into TransitionError) when operation is not allowed in current client state
+	ErrInvalidTransition = errors.New("invalid client state transition")
+)
+
+// ConnectionError represents failure to initialize connection to specific server
+type ConnectionError struct {
+	Server     string
+	DataCenter string
+	Err        error
+}
+
+// Error returns error message
+func (err *ConnectionError) Error() string {
+	return fmt.Sprintf(
+		"failed to initialize connection to %s (datacenter: %s) - %s",
+		err.Server,
+		err.DataCenter,
+		err.Err.Error(),
+	)
+}
+
+// Unwrap returns underlying error
+func (err *ConnectionError) Unwrap() error {
+	return err.Err
+}
+
+// TransitionError represents rejected transition between client lifecycle states
+type TransitionError struct {
+	From state.State
+	To   state.State
+}
+
+// Error returns error message
+func (err *TransitionError) Error() string {
+	return fmt.Sprintf("client cannot move from %s to %s", err.From.String(), err.To.String())
+}
+
+// Unwrap returns ErrInvalidTransition
+func (err *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// IsConnectivityError indicates whether error is caused by failure to reach Consul server, responses rejected by Consul
+// (including HTTP-level rejections such as 400, 403 or 413) are not connectivity errors
+func IsConnectivityError(err error) bool {
+	var urlError *url.Error
+	var netError net.Error
+	return errors.As(err, &urlError) || errors.As(err, &netError)
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestIsConnectivityError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "no error"},
+		{name: "request failed", err: &url.Error{Op: "Get", URL: "http://localhost:8500/v1/agent/services", Err: errors.New("connection refused")}, want: true},
+		{name: "wrapped network error", err: fmt.Errorf("request failed - %w", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}), want: true},
+		{name: "rejected request", err: errors.New("Unexpected response code: 400 (Invalid check)")},
+		{name: "not connected", err: ErrNotConnected},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := IsConnectivityError(test.err); got != test.want {
+				t.Errorf("IsConnectivityError(%v) = %t, want %t", test.err, got, test.want)
+			}
+		})
+	}
+}
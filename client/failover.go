@@ -0,0 +1,182 @@
+package client
+
+import (
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/state"
+)
+
+const (
+	defaultFailoverInterval  = 10 * time.Second
+	defaultFailoverThreshold = 3
+)
+
+// WithFailover sets how often active server is checked and after how many consecutive failures client switches to another server
+func (client *Client) WithFailover(interval time.Duration, threshold int) *Client {
+	client.failoverDisabled = false
+	client.failoverInterval = interval
+	client.failoverThreshold = threshold
+	return client
+}
+
+// WithoutFailover disables monitoring of active server
+func (client *Client) WithoutFailover() *Client {
+	client.failoverDisabled = true
+	return client
+}
+
+// Failover selects new server and rebuilds Consul API client, or returns error
+func (client *Client) Failover() error {
+	if client.State() != state.ConsulStarted {
+		return ErrNotConnected
+	}
+	client.Broker().Publish(state.ConsulFailoverStarted)
+
+	server, rtt := client.bestServer()
+	if server == nil {
+		client.Metrics().Failover(ErrNoServersAvailable)
+		client.Broker().Publish(state.ConsulFailoverFailed)
+		return ErrNoServersAvailable
+	}
+
+	client.mutex.Lock()
+	previous := client.server
+	client.server = server
+	client.configureAPIClient()
+	apiClient, err := consulAPI.NewClient(client.apiConfig)
+	if err != nil {
+		client.server = previous
+		client.mutex.Unlock()
+		client.Metrics().Failover(err)
+		client.Broker().Publish(state.ConsulFailoverFailed)
+		return &ConnectionError{
+			Server:     server.HostPort(),
+			DataCenter: server.DataCenter(),
+			Err:        err,
+		}
+	}
+	client.apiClient = apiClient
+	client.mutex.Unlock()
+	client.Metrics().Failover(nil)
+	client.Metrics().ServerSelected(server.HostPort())
+
+	client.Logger().With("server", server.HostPort()).Infof(
+		"consul:client",
+		"failed over to %s (datacenter: %s) with ping of %dms",
+		server.HostPort(),
+		server.DataCenter(),
+		rtt,
+	)
+	state.Publish(client.Broker(), state.ServerSelected{
+		Server:     server.HostPort(),
+		DataCenter: server.DataCenter(),
+		RTT:        time.Duration(rtt) * time.Millisecond,
+	})
+	client.Broker().Publish(state.ConsulStarted)
+	client.Broker().Publish(state.ConsulFailoverCompleted)
+	return nil
+}
+
+// startMonitor starts monitoring of active server (and handling of restart requests) in the background
+func (client *Client) startMonitor() {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if client.monitorQuit != nil || (client.failoverDisabled && client.supervisor == nil) {
+		return
+	}
+
+	interval := client.failoverInterval
+	if interval <= 0 {
+		interval = defaultFailoverInterval
+	}
+
+	threshold := client.failoverThreshold
+	if threshold <= 0 {
+		threshold = defaultFailoverThreshold
+	}
+
+	client.monitorQuit = make(chan struct{})
+	client.monitorDone = make(chan struct{})
+	go client.monitor(client.monitorQuit, client.monitorDone, !client.failoverDisabled, interval, threshold)
+}
+
+// stopMonitor stops monitoring of active server and waits for it to finish
+func (client *Client) stopMonitor() {
+	client.mutex.Lock()
+	quit, done := client.monitorQuit, client.monitorDone
+	client.monitorQuit = nil
+	client.monitorDone = nil
+	client.mutex.Unlock()
+
+	if quit == nil {
+		return
+	}
+	close(quit)
+	<-done
+}
+
+// monitor periodically checks active server (if checks are enabled) and fails over once it is considered dead or restart is requested
+func (client *Client) monitor(quit <-chan struct{}, done chan<- struct{}, checks bool, interval time.Duration, threshold int) {
+	defer close(done)
+
+	events := client.Broker().Subscribe()
+	defer client.Broker().Unsubscribe(events)
+
+	var tickerChannel <-chan time.Time
+	if checks {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickerChannel = ticker.C
+	}
+
+	failures := 0
+	var lastFailover time.Time
+	var restartChannel <-chan time.Time
+
+	for {
+		select {
+		case <-quit:
+			return
+		case event := <-events:
+			if !state.Is(event, state.ConsulRestartRequested) || restartChannel != nil || time.Since(lastFailover) < interval {
+				continue
+			}
+			restartChannel = time.After(client.restartDebounce())
+		case <-restartChannel:
+			restartChannel = nil
+			client.Logger().Warnf("consul:client", "restart requested, selecting new server")
+			if err := client.restart(quit); err != nil {
+				client.Logger().Errorf("consul:client", "failover failed - %s", err.Error())
+			} else {
+				failures = 0
+			}
+			lastFailover = time.Now()
+		case <-tickerChannel:
+			server := client.Server()
+			if server == nil {
+				continue
+			}
+			// Server without cluster leader keeps serving requests, so only unreachable server counts as failure
+			ctx, cancel := server.probeContext()
+			reachable := server.Probe(ctx).Reachable
+			cancel()
+			if reachable {
+				failures = 0
+				continue
+			}
+			failures++
+			client.Logger().With("server", server.HostPort()).Warnf("consul:client", "server %s is not responding (%d/%d)", server.HostPort(), failures, threshold)
+			if failures < threshold {
+				continue
+			}
+			if err := client.restart(quit); err != nil {
+				client.Logger().Errorf("consul:client", "failover failed - %s", err.Error())
+			} else {
+				failures = 0
+			}
+			lastFailover = time.Now()
+		}
+	}
+}
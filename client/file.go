@@ -0,0 +1,160 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"gopkg.in/yaml.v3"
+)
+
+// connectionsFile represents structure of file with connections
+type connectionsFile struct {
+	Servers []fileConnection `json:"servers" yaml:"servers"`
+}
+
+// fileConnection represents structure of connection defined in file
+type fileConnection struct {
+	Scheme      string   `json:"scheme" yaml:"scheme"`
+	Host        string   `json:"host" yaml:"host"`
+	Port        uint     `json:"port" yaml:"port"`
+	DataCenter  string   `json:"datacenter" yaml:"datacenter"`
+	AccessToken string   `json:"token" yaml:"token"`
+	TokenFile   string   `json:"token_file" yaml:"token_file"`
+	Weight      uint     `json:"weight" yaml:"weight"`
+	Priority    int      `json:"priority" yaml:"priority"`
+	TLS         *fileTLS `json:"tls" yaml:"tls"`
+}
+
+// fileTLS represents structure of TLS configuration defined in file
+type fileTLS struct {
+	CAFile             string `json:"ca_file" yaml:"ca_file"`
+	CAPem              string `json:"ca_pem" yaml:"ca_pem"`
+	CertFile           string `json:"cert_file" yaml:"cert_file"`
+	KeyFile            string `json:"key_file" yaml:"key_file"`
+	ServerName         string `json:"server_name" yaml:"server_name"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+}
+
+// hclConnectionsFile represents structure of HCL file with connections (HCL blocks are decoded as lists)
+type hclConnectionsFile struct {
+	Servers []hclConnection `json:"server"`
+}
+
+// hclConnection represents structure of connection defined in HCL file
+type hclConnection struct {
+	fileConnection
+	TLS []fileTLS `json:"tls"`
+}
+
+// FromFile creates client from JSON, YAML or HCL file with connections
+func FromFile(path string) (*Client, error) {
+	connections, err := ConnectionsFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return MultipleServers(connections), nil
+}
+
+// ConnectionsFromFile creates list of connections from JSON, YAML or HCL file (format is detected by extension)
+func ConnectionsFromFile(path string) ([]*ConnectionInformation, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file connectionsFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".hcl":
+		err = decodeHCL(data, &file)
+	default:
+		return nil, fmt.Errorf("unsupported configuration file format %s", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file %s - %w", path, err)
+	}
+
+	if len(file.Servers) == 0 {
+		return nil, fmt.Errorf("no consul servers defined in %s", path)
+	}
+
+	connections := make([]*ConnectionInformation, 0, len(file.Servers))
+	for _, server := range file.Servers {
+		connection, err := server.connection()
+		if err != nil {
+			return nil, err
+		}
+		// Datacenter is left empty when it is not specified, so agent's own datacenter is used instead of "dc0"
+		connections = append(connections, NewConnection(connection).SetDataCenter(connection.DataCenter))
+	}
+	return connections, nil
+}
+
+// connection converts connection defined in file to connection object
+func (server fileConnection) connection() (*Connection, error) {
+	connection := &Connection{
+		Scheme:      server.Scheme,
+		Host:        server.Host,
+		Port:        server.Port,
+		DataCenter:  server.DataCenter,
+		AccessToken: server.AccessToken,
+		Weight:      server.Weight,
+		Priority:    server.Priority,
+	}
+
+	if connection.AccessToken == "" && server.TokenFile != "" {
+		token, err := readTokenFile(server.TokenFile)
+		if err != nil {
+			return nil, err
+		}
+		connection.AccessToken = token
+	}
+
+	if server.TLS != nil {
+		connection.TLS = &TLS{
+			CAFile:             server.TLS.CAFile,
+			CertFile:           server.TLS.CertFile,
+			KeyFile:            server.TLS.KeyFile,
+			ServerName:         server.TLS.ServerName,
+			InsecureSkipVerify: server.TLS.InsecureSkipVerify,
+		}
+		if server.TLS.CAPem != "" {
+			connection.TLS.CAPem = []byte(server.TLS.CAPem)
+		}
+	}
+	return connection, nil
+}
+
+// decodeHCL decodes HCL file with `server` blocks
+func decodeHCL(data []byte, file *connectionsFile) error {
+	var raw map[string]interface{}
+	if err := hcl.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	var hclFile hclConnectionsFile
+	if err := json.Unmarshal(encoded, &hclFile); err != nil {
+		return err
+	}
+
+	for _, server := range hclFile.Servers {
+		connection := server.fileConnection
+		if len(server.TLS) > 0 {
+			connection.TLS = &server.TLS[0]
+		}
+		file.Servers = append(file.Servers, connection)
+	}
+	return nil
+}
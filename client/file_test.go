@@ -0,0 +1,141 @@
+package client
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestConnectionsFromFile(t *testing.T) {
+	directory := t.TempDir()
+	tokenFile := filepath.Join(directory, "consul.token")
+	if err := ioutil.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatalf("unable to write token file - %s", err)
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		content    string
+		want       string
+		token      string
+		dataCenter string
+		serverName string
+		wantErr    bool
+	}{
+		{
+			name: "json",
+			file: "consul.json",
+			content: `{"servers": [{"host": "consul.local", "port": 8501, "scheme": "https", "datacenter": "dc1", "token": "token",
+				"tls": {"server_name": "consul.local", "insecure_skip_verify": true}}]}`,
+			want:       "https://consul.local:8501",
+			token:      "token",
+			dataCenter: "dc1",
+			serverName: "consul.local",
+		},
+		{
+			name: "yaml",
+			file: "consul.yml",
+			content: `servers:
+  - host: consul.local
+    port: 8501
+    datacenter: dc1
+    token_file: ` + tokenFile + `
+    tls:
+      server_name: consul.local
+`,
+			want:       "https://consul.local:8501",
+			token:      "file-token",
+			dataCenter: "dc1",
+			serverName: "consul.local",
+		},
+		{
+			name: "hcl",
+			file: "consul.hcl",
+			content: `server {
+  host       = "consul.local"
+  port       = 8501
+  datacenter = "dc1"
+  token      = "token"
+  tls {
+    server_name = "consul.local"
+  }
+}
+`,
+			want:       "https://consul.local:8501",
+			token:      "token",
+			dataCenter: "dc1",
+			serverName: "consul.local",
+		},
+		{
+			name:    "hcl without tls",
+			file:    "plain.hcl",
+			content: `server { host = "consul.local" }`,
+			want:    "http://consul.local:8500",
+		},
+		{
+			name:    "unsupported format",
+			file:    "consul.toml",
+			content: `servers = []`,
+			wantErr: true,
+		},
+		{
+			name:    "no servers",
+			file:    "empty.json",
+			content: `{"servers": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid content",
+			file:    "invalid.json",
+			content: `{"servers": `,
+			wantErr: true,
+		},
+		{
+			name:    "missing token file",
+			file:    "missing.json",
+			content: `{"servers": [{"host": "consul.local", "token_file": "` + filepath.Join(directory, "missing") + `"}]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(directory, test.file)
+			if err := ioutil.WriteFile(path, []byte(test.content), 0600); err != nil {
+				t.Fatalf("unable to write configuration file - %s", err)
+			}
+
+			connections, err := ConnectionsFromFile(path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ConnectionsFromFile() error = %v, want error %t", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if len(connections) != 1 {
+				t.Fatalf("ConnectionsFromFile() returned %d connections, want 1", len(connections))
+			}
+
+			connection := connections[0]
+			if connection.FullPath() != test.want {
+				t.Errorf("FullPath() = %q, want %q", connection.FullPath(), test.want)
+			}
+			if connection.AccessToken() != test.token {
+				t.Errorf("AccessToken() = %q, want %q", connection.AccessToken(), test.token)
+			}
+			if connection.DataCenter() != test.dataCenter {
+				t.Errorf("DataCenter() = %q, want %q", connection.DataCenter(), test.dataCenter)
+			}
+			if test.serverName == "" {
+				if connection.UsesTLS() {
+					t.Errorf("TLS() = %+v, want no TLS", connection.TLS())
+				}
+				return
+			}
+			if !connection.UsesTLS() || connection.TLS().ServerName != test.serverName {
+				t.Errorf("TLS() = %+v, want server name %q", connection.TLS(), test.serverName)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+
+	"github.com/leads-su/consul/state"
+)
+
+// transitions contains states client is allowed to move to from each state of its lifecycle
+var transitions = map[state.State][]state.State{
+	state.ConsulConfigurationPending: {state.ConsulConfigured},
+	state.ConsulConfigured:           {state.ConsulConfigured, state.ConsulStarting},
+	state.ConsulStarting:             {state.ConsulStarted, state.ConsulConfigured},
+	state.ConsulStarted:              {state.ConsulShuttingDown},
+	state.ConsulShuttingDown:         {state.ConsulConfigurationPending},
+}
+
+// State returns current lifecycle state of the client
+func (client *Client) State() state.State {
+	client.stateMutex.Lock()
+	defer client.stateMutex.Unlock()
+	return client.currentState()
+}
+
+// WaitForState blocks until client reaches given state or context is done
+func (client *Client) WaitForState(ctx context.Context, expected state.State) error {
+	for {
+		client.stateMutex.Lock()
+		if client.currentState() == expected {
+			client.stateMutex.Unlock()
+			return nil
+		}
+		changed := client.stateChanged()
+		client.stateMutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// transition moves client to the given state, or returns TransitionError if transition is not allowed
+func (client *Client) transition(to state.State) error {
+	client.stateMutex.Lock()
+	defer client.stateMutex.Unlock()
+
+	from := client.currentState()
+	allowed := false
+	for _, candidate := range transitions[from] {
+		if candidate == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return &TransitionError{From: from, To: to}
+	}
+
+	client.state = to
+	if client.changed != nil {
+		close(client.changed)
+		client.changed = nil
+	}
+	return nil
+}
+
+// currentState returns current state, treating uninitialized state as pending configuration (must be called with stateMutex held)
+func (client *Client) currentState() state.State {
+	if client.state == 0 {
+		return state.ConsulConfigurationPending
+	}
+	return client.state
+}
+
+// stateChanged returns channel which is closed on next transition (must be called with stateMutex held)
+func (client *Client) stateChanged() <-chan struct{} {
+	if client.changed == nil {
+		client.changed = make(chan struct{})
+	}
+	return client.changed
+}
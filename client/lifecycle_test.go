@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leads-su/consul/state"
+)
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []state.State
+		to    state.State
+		valid bool
+	}{
+		{name: "pending to configured", to: state.ConsulConfigured, valid: true},
+		{name: "pending to started", to: state.ConsulStarted},
+		{name: "configured again", path: []state.State{state.ConsulConfigured}, to: state.ConsulConfigured, valid: true},
+		{name: "configured to starting", path: []state.State{state.ConsulConfigured}, to: state.ConsulStarting, valid: true},
+		{name: "configured to shutting down", path: []state.State{state.ConsulConfigured}, to: state.ConsulShuttingDown},
+		{name: "starting to started", path: []state.State{state.ConsulConfigured, state.ConsulStarting}, to: state.ConsulStarted, valid: true},
+		{name: "starting back to configured", path: []state.State{state.ConsulConfigured, state.ConsulStarting}, to: state.ConsulConfigured, valid: true},
+		{name: "started to shutting down", path: []state.State{state.ConsulConfigured, state.ConsulStarting, state.ConsulStarted}, to: state.ConsulShuttingDown, valid: true},
+		{name: "started to starting", path: []state.State{state.ConsulConfigured, state.ConsulStarting, state.ConsulStarted}, to: state.ConsulStarting},
+		{name: "shutting down to pending", path: []state.State{state.ConsulConfigured, state.ConsulStarting, state.ConsulStarted, state.ConsulShuttingDown}, to: state.ConsulConfigurationPending, valid: true},
+		{name: "shutting down to started", path: []state.State{state.ConsulConfigured, state.ConsulStarting, state.ConsulStarted, state.ConsulShuttingDown}, to: state.ConsulStarted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &Client{}
+			for _, step := range test.path {
+				if err := client.transition(step); err != nil {
+					t.Fatalf("transition(%s) returned error - %s", step, err)
+				}
+			}
+			from := client.State()
+
+			err := client.transition(test.to)
+			if test.valid {
+				if err != nil {
+					t.Fatalf("transition(%s) returned error - %s", test.to, err)
+				}
+				if client.State() != test.to {
+					t.Errorf("State() = %s, want %s", client.State(), test.to)
+				}
+				return
+			}
+
+			var transitionError *TransitionError
+			if !errors.As(err, &transitionError) || !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("transition(%s) error = %v, want TransitionError", test.to, err)
+			}
+			if transitionError.From != from || transitionError.To != test.to {
+				t.Errorf("TransitionError = %s -> %s, want %s -> %s", transitionError.From, transitionError.To, from, test.to)
+			}
+			if client.State() != from {
+				t.Errorf("State() = %s after rejected transition, want %s", client.State(), from)
+			}
+		})
+	}
+}
+
+func TestWaitForState(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected state.State
+		path     []state.State
+		wantErr  error
+	}{
+		{name: "current state", expected: state.ConsulConfigurationPending},
+		{name: "reached after transitions", expected: state.ConsulStarting, path: []state.State{state.ConsulConfigured, state.ConsulStarting}},
+		{name: "never reached", expected: state.ConsulStarted, path: []state.State{state.ConsulConfigured}, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := &Client{}
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			go func(path []state.State) {
+				for _, step := range path {
+					client.transition(step)
+				}
+			}(test.path)
+
+			if err := client.WaitForState(ctx, test.expected); !errors.Is(err, test.wantErr) {
+				t.Errorf("WaitForState() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ProbeResult represents structure of server probe result
+type ProbeResult struct {
+	Server       *ConnectionInformation
+	Reachable    bool          // Server responded to the status request
+	Leader       string        // Address of the cluster leader reported by server
+	AgentVersion string        // Consul version reported by agent (only when agent is probed)
+	Latency      time.Duration // Time it took to receive leader information
+	Error        error         // Error of the leader request, server is not available if it is set
+	AgentError   error         // Error of the agent request, it does not affect availability of the server
+}
+
+// HasLeader indicates whether server reported cluster leader
+func (result ProbeResult) HasLeader() bool {
+	return result.Leader != ""
+}
+
+// Available indicates whether server is reachable and has cluster leader
+func (result ProbeResult) Available() bool {
+	return result.Reachable && result.HasLeader() && result.Error == nil
+}
+
+// agentSelfResponse represents part of '/v1/agent/self' response used by probe
+type agentSelfResponse struct {
+	Config struct {
+		Version string `json:"Version"`
+	} `json:"Config"`
+}
+
+// Probe queries cluster leader through '/v1/status/leader'
+func (information *ConnectionInformation) Probe(ctx context.Context) ProbeResult {
+	return information.probe(ctx, false)
+}
+
+// ProbeWithAgent queries cluster leader through '/v1/status/leader' and agent version through '/v1/agent/self'
+func (information *ConnectionInformation) ProbeWithAgent(ctx context.Context) ProbeResult {
+	return information.probe(ctx, true)
+}
+
+// probe performs probe requests against server
+func (information *ConnectionInformation) probe(ctx context.Context, agent bool) ProbeResult {
+	result := ProbeResult{
+		Server: information,
+	}
+
+	transport, err := information.transport()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	defer transport.CloseIdleConnections()
+
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+
+	start := time.Now()
+	if err := information.get(ctx, httpClient, "/v1/status/leader", &result.Leader); err != nil {
+		result.Error = err
+		return result
+	}
+	result.Latency = time.Since(start)
+	result.Reachable = true
+
+	if agent {
+		var self agentSelfResponse
+		if err := information.get(ctx, httpClient, "/v1/agent/self", &self); err != nil {
+			result.AgentError = err
+		} else {
+			result.AgentVersion = self.Config.Version
+		}
+	}
+
+	return result
+}
+
+// get performs authorized GET request against server and decodes JSON response
+func (information *ConnectionInformation) get(ctx context.Context, httpClient *http.Client, path string, target interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, information.FullPath()+path, nil)
+	if err != nil {
+		return err
+	}
+	if information.UsesAccessToken() {
+		request.Header.Set("X-Consul-Token", information.AccessToken())
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status from %s - %s", path, response.Status)
+	}
+	return json.NewDecoder(response.Body).Decode(target)
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// consulServer represents structure of fake Consul server used by probe tests
+type consulServer struct {
+	leader       string
+	leaderStatus int
+	agentStatus  int
+	tokens       []string
+}
+
+// ServeHTTP responds to leader and agent requests
+func (server *consulServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	server.tokens = append(server.tokens, request.Header.Get("X-Consul-Token"))
+
+	status, body := server.leaderStatus, interface{}(server.leader)
+	if request.URL.Path == "/v1/agent/self" {
+		status, body = server.agentStatus, map[string]interface{}{"Config": map[string]string{"Version": "1.15.0"}}
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(body)
+}
+
+// connectionTo creates connection to test server
+func connectionTo(t *testing.T, server *httptest.Server, token string) *ConnectionInformation {
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to parse test server address - %s", err)
+	}
+	parsedPort, _ := strconv.ParseUint(port, 10, 16)
+	return NewConnection(&Connection{Host: host, Port: uint(parsedPort), AccessToken: token})
+}
+
+func TestProbe(t *testing.T) {
+	tests := []struct {
+		name          string
+		server        consulServer
+		agent         bool
+		token         string
+		wantReachable bool
+		wantLeader    string
+		wantVersion   string
+		wantAvailable bool
+		wantErr       bool
+		wantAgentErr  bool
+	}{
+		{
+			name:          "leader present",
+			server:        consulServer{leader: "10.0.0.1:8300"},
+			wantReachable: true,
+			wantLeader:    "10.0.0.1:8300",
+			wantAvailable: true,
+		},
+		{
+			name:          "no leader",
+			server:        consulServer{leader: ""},
+			wantReachable: true,
+		},
+		{
+			name:    "unexpected status",
+			server:  consulServer{leader: "10.0.0.1:8300", leaderStatus: http.StatusInternalServerError},
+			wantErr: true,
+		},
+		{
+			name:          "access token",
+			server:        consulServer{leader: "10.0.0.1:8300"},
+			token:         "secret",
+			wantReachable: true,
+			wantLeader:    "10.0.0.1:8300",
+			wantAvailable: true,
+		},
+		{
+			name:          "with agent",
+			server:        consulServer{leader: "10.0.0.1:8300"},
+			agent:         true,
+			token:         "secret",
+			wantReachable: true,
+			wantLeader:    "10.0.0.1:8300",
+			wantVersion:   "1.15.0",
+			wantAvailable: true,
+		},
+		{
+			name:          "agent request failed",
+			server:        consulServer{leader: "10.0.0.1:8300", agentStatus: http.StatusForbidden},
+			agent:         true,
+			wantReachable: true,
+			wantLeader:    "10.0.0.1:8300",
+			wantAvailable: true,
+			wantAgentErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			consul := test.server
+			server := httptest.NewServer(&consul)
+			defer server.Close()
+			connection := connectionTo(t, server, test.token)
+
+			var result ProbeResult
+			if test.agent {
+				result = connection.ProbeWithAgent(context.Background())
+			} else {
+				result = connection.Probe(context.Background())
+			}
+			server.Close()
+
+			if (result.Error != nil) != test.wantErr {
+				t.Errorf("Error = %v, want error %t", result.Error, test.wantErr)
+			}
+			if (result.AgentError != nil) != test.wantAgentErr {
+				t.Errorf("AgentError = %v, want error %t", result.AgentError, test.wantAgentErr)
+			}
+			if result.Reachable != test.wantReachable {
+				t.Errorf("Reachable = %t, want %t", result.Reachable, test.wantReachable)
+			}
+			if result.Leader != test.wantLeader {
+				t.Errorf("Leader = %q, want %q", result.Leader, test.wantLeader)
+			}
+			if result.AgentVersion != test.wantVersion {
+				t.Errorf("AgentVersion = %q, want %q", result.AgentVersion, test.wantVersion)
+			}
+			if result.Available() != test.wantAvailable {
+				t.Errorf("Available() = %t, want %t", result.Available(), test.wantAvailable)
+			}
+			if result.Server != connection {
+				t.Errorf("Server = %p, want probed connection %p", result.Server, connection)
+			}
+			for _, token := range consul.tokens {
+				if token != test.token {
+					t.Errorf("X-Consul-Token = %q, want %q", token, test.token)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SelectionStrategy selects server to connect to from the list of available (already pinged) servers
+type SelectionStrategy interface {
+	Select(servers []PingedServer) *PingedServer
+}
+
+// LowestLatencyStrategy selects server with the lowest round trip time
+type LowestLatencyStrategy struct{}
+
+// NewLowestLatencyStrategy creates new instance of lowest latency strategy
+func NewLowestLatencyStrategy() *LowestLatencyStrategy {
+	return &LowestLatencyStrategy{}
+}
+
+// Select selects server with the lowest round trip time
+func (strategy *LowestLatencyStrategy) Select(servers []PingedServer) *PingedServer {
+	var bestServer *PingedServer
+	for index := range servers {
+		if bestServer == nil || servers[index].rtt < bestServer.rtt {
+			bestServer = &servers[index]
+		}
+	}
+	return bestServer
+}
+
+// RoundRobinStrategy selects available servers one after another on each selection
+type RoundRobinStrategy struct {
+	mutex sync.Mutex
+	next  int
+}
+
+// NewRoundRobinStrategy creates new instance of round-robin strategy
+func NewRoundRobinStrategy() *RoundRobinStrategy {
+	return &RoundRobinStrategy{}
+}
+
+// Select selects next available server
+func (strategy *RoundRobinStrategy) Select(servers []PingedServer) *PingedServer {
+	if len(servers) == 0 {
+		return nil
+	}
+	strategy.mutex.Lock()
+	defer strategy.mutex.Unlock()
+	selected := &servers[strategy.next%len(servers)]
+	strategy.next++
+	return selected
+}
+
+// RandomWeightedStrategy selects random server, servers with higher weight are selected more often
+type RandomWeightedStrategy struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+// NewRandomWeightedStrategy creates new instance of random weighted strategy
+func NewRandomWeightedStrategy() *RandomWeightedStrategy {
+	return &RandomWeightedStrategy{
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Select selects random server according to its weight
+func (strategy *RandomWeightedStrategy) Select(servers []PingedServer) *PingedServer {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	var total uint
+	for _, server := range servers {
+		total += serverWeight(server.server)
+	}
+
+	strategy.mutex.Lock()
+	pick := uint(strategy.random.Int63n(int64(total)))
+	strategy.mutex.Unlock()
+
+	for index, server := range servers {
+		weight := serverWeight(server.server)
+		if pick < weight {
+			return &servers[index]
+		}
+		pick -= weight
+	}
+	return &servers[len(servers)-1]
+}
+
+// PriorityStrategy selects server with the lowest priority value, ties are resolved by round trip time
+type PriorityStrategy struct{}
+
+// NewPriorityStrategy creates new instance of priority strategy
+func NewPriorityStrategy() *PriorityStrategy {
+	return &PriorityStrategy{}
+}
+
+// Select selects server with the lowest priority value
+func (strategy *PriorityStrategy) Select(servers []PingedServer) *PingedServer {
+	if len(servers) == 0 {
+		return nil
+	}
+	sorted := make([]PingedServer, len(servers))
+	copy(sorted, servers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].server.Priority() != sorted[j].server.Priority() {
+			return sorted[i].server.Priority() < sorted[j].server.Priority()
+		}
+		return sorted[i].rtt < sorted[j].rtt
+	})
+	return &sorted[0]
+}
+
+// SameDataCenterStrategy prefers servers from specified datacenter and falls back to all servers if there are none
+type SameDataCenterStrategy struct {
+	dataCenter string
+	next       SelectionStrategy
+}
+
+// NewSameDataCenterStrategy creates new instance of same datacenter strategy, next strategy defaults to lowest latency
+func NewSameDataCenterStrategy(dataCenter string, next SelectionStrategy) *SameDataCenterStrategy {
+	if next == nil {
+		next = NewLowestLatencyStrategy()
+	}
+	return &SameDataCenterStrategy{
+		dataCenter: dataCenter,
+		next:       next,
+	}
+}
+
+// Select selects server from preferred datacenter using next strategy
+func (strategy *SameDataCenterStrategy) Select(servers []PingedServer) *PingedServer {
+	var sameDataCenter []PingedServer
+	for _, server := range servers {
+		if server.server.DataCenter() == strategy.dataCenter {
+			sameDataCenter = append(sameDataCenter, server)
+		}
+	}
+	if len(sameDataCenter) == 0 {
+		return strategy.next.Select(servers)
+	}
+	return strategy.next.Select(sameDataCenter)
+}
+
+// LeaderReachableStrategy prefers servers which reported cluster leader in their probe and falls back to all servers if there are none
+type LeaderReachableStrategy struct {
+	next SelectionStrategy
+}
+
+// NewLeaderReachableStrategy creates new instance of leader reachable strategy, next strategy defaults to lowest latency
+func NewLeaderReachableStrategy(next SelectionStrategy) *LeaderReachableStrategy {
+	if next == nil {
+		next = NewLowestLatencyStrategy()
+	}
+	return &LeaderReachableStrategy{
+		next: next,
+	}
+}
+
+// Select selects server which reports cluster leader using next strategy
+func (strategy *LeaderReachableStrategy) Select(servers []PingedServer) *PingedServer {
+	var withLeader []PingedServer
+	for _, server := range servers {
+		if server.probe.HasLeader() {
+			withLeader = append(withLeader, server)
+		}
+	}
+	if len(withLeader) == 0 {
+		return strategy.next.Select(servers)
+	}
+	return strategy.next.Select(withLeader)
+}
+
+// serverWeight returns server weight treating zero as one
+func serverWeight(server *ConnectionInformation) uint {
+	if server.Weight() == 0 {
+		return 1
+	}
+	return server.Weight()
+}
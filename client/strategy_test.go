@@ -0,0 +1,118 @@
+package client
+
+import (
+	"testing"
+)
+
+// pinged creates pinged server for strategy tests
+func pinged(host string, rtt int64, connection Connection, leader bool) PingedServer {
+	connection.Host = host
+	server := NewConnection(&connection)
+	probe := ProbeResult{Server: server, Reachable: true}
+	if leader {
+		probe.Leader = "10.0.0.1:8300"
+	}
+	return PingedServer{server: server, rtt: rtt, probe: probe}
+}
+
+// selectedHost returns host of selected server or empty string if nothing is selected
+func selectedHost(selected *PingedServer) string {
+	if selected == nil {
+		return ""
+	}
+	return selected.Server().Host()
+}
+
+func TestSelectionStrategies(t *testing.T) {
+	servers := []PingedServer{
+		pinged("first", 30, Connection{DataCenter: "dc1", Priority: 2}, true),
+		pinged("second", 10, Connection{DataCenter: "dc2", Priority: 1}, false),
+		pinged("third", 20, Connection{DataCenter: "dc1", Priority: 1}, true),
+	}
+
+	tests := []struct {
+		name     string
+		strategy SelectionStrategy
+		servers  []PingedServer
+		want     string
+	}{
+		{name: "lowest latency", strategy: NewLowestLatencyStrategy(), servers: servers, want: "second"},
+		{name: "lowest latency without servers", strategy: NewLowestLatencyStrategy(), want: ""},
+		{name: "priority resolves ties by latency", strategy: NewPriorityStrategy(), servers: servers, want: "second"},
+		{name: "priority without servers", strategy: NewPriorityStrategy(), want: ""},
+		{name: "same datacenter", strategy: NewSameDataCenterStrategy("dc1", nil), servers: servers, want: "third"},
+		{name: "same datacenter with next strategy", strategy: NewSameDataCenterStrategy("dc1", NewPriorityStrategy()), servers: servers, want: "third"},
+		{name: "same datacenter falls back to all servers", strategy: NewSameDataCenterStrategy("dc3", nil), servers: servers, want: "second"},
+		{name: "leader reachable", strategy: NewLeaderReachableStrategy(nil), servers: servers, want: "third"},
+		{name: "leader reachable falls back to all servers", strategy: NewLeaderReachableStrategy(nil), servers: servers[1:2], want: "second"},
+		{name: "round robin without servers", strategy: NewRoundRobinStrategy(), want: ""},
+		{name: "random weighted without servers", strategy: NewRandomWeightedStrategy(), want: ""},
+		{name: "random weighted with single server", strategy: NewRandomWeightedStrategy(), servers: servers[:1], want: "first"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if host := selectedHost(test.strategy.Select(test.servers)); host != test.want {
+				t.Errorf("Select() = %q, want %q", host, test.want)
+			}
+		})
+	}
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	servers := []PingedServer{
+		pinged("first", 10, Connection{}, true),
+		pinged("second", 20, Connection{}, true),
+		pinged("third", 30, Connection{}, true),
+	}
+	strategy := NewRoundRobinStrategy()
+
+	for _, want := range []string{"first", "second", "third", "first"} {
+		if host := selectedHost(strategy.Select(servers)); host != want {
+			t.Errorf("Select() = %q, want %q", host, want)
+		}
+	}
+}
+
+func TestRandomWeightedStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []PingedServer
+		want    string
+	}{
+		{
+			name: "heavier server is selected more often",
+			servers: []PingedServer{
+				pinged("light", 10, Connection{Weight: 1}, true),
+				pinged("heavy", 10, Connection{Weight: 99}, true),
+			},
+			want: "heavy",
+		},
+		{
+			name: "zero weight is treated as one",
+			servers: []PingedServer{
+				pinged("default", 10, Connection{}, true),
+				pinged("heavy", 10, Connection{Weight: 50}, true),
+			},
+			want: "heavy",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strategy := NewRandomWeightedStrategy()
+			selections := make(map[string]int)
+			for index := 0; index < 1000; index++ {
+				selections[selectedHost(strategy.Select(test.servers))]++
+			}
+			for host, count := range selections {
+				if host != test.want && count >= selections[test.want] {
+					t.Errorf("server %q was selected %d times, more than %q (%d times)", host, count, test.want, selections[test.want])
+				}
+			}
+			if len(selections) > len(test.servers) {
+				t.Errorf("selected servers %v are not in the list", selections)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+const (
+	defaultSupervisorDebounce       = 5 * time.Second
+	defaultSupervisorInitialBackoff = 1 * time.Second
+	defaultSupervisorMaxBackoff     = 30 * time.Second
+	defaultSupervisorMaxAttempts    = 5
+)
+
+// Registrant is implemented by components (e.g. services) which have to be registered again after client reconnects
+type Registrant interface {
+	Reregister() error
+}
+
+// SupervisorOptions represents structure of restart supervisor options
+type SupervisorOptions struct {
+	Debounce       time.Duration // Period restart requests are collected for before acting on them | Defaults to 5 seconds
+	InitialBackoff time.Duration // Delay before second attempt | Defaults to 1 second
+	MaxBackoff     time.Duration // Maximum delay between attempts | Defaults to 30 seconds
+	MaxAttempts    int           // Maximum number of attempts per restart request | Defaults to 5
+}
+
+// WithSupervisor enables supervisor which acts on restart requests by selecting new server, reconnecting and re-registering attached services
+func (client *Client) WithSupervisor(options SupervisorOptions) *Client {
+	if options.Debounce <= 0 {
+		options.Debounce = defaultSupervisorDebounce
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = defaultSupervisorInitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaultSupervisorMaxBackoff
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultSupervisorMaxAttempts
+	}
+	client.mutex.Lock()
+	client.supervisor = &options
+	client.mutex.Unlock()
+	return client
+}
+
+// Attach attaches component which is registered again once supervisor restores connection
+func (client *Client) Attach(registrant Registrant) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.registrants = append(client.registrants, registrant)
+}
+
+// supervisorOptions returns supervisor options, or nil if supervisor is not enabled
+func (client *Client) supervisorOptions() *SupervisorOptions {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	return client.supervisor
+}
+
+// restartDebounce returns period restart requests are collected for before acting on them
+func (client *Client) restartDebounce() time.Duration {
+	if options := client.supervisorOptions(); options != nil {
+		return options.Debounce
+	}
+	return 0
+}
+
+// restart selects new server and reconnects, retrying with back off and re-registering attached components if supervisor is enabled
+func (client *Client) restart(quit <-chan struct{}) error {
+	options := client.supervisorOptions()
+	if options == nil {
+		return client.Failover()
+	}
+
+	policy := backoff.NewExponentialBackOff()
+	policy.InitialInterval = options.InitialBackoff
+	policy.MaxInterval = options.MaxBackoff
+	policy.MaxElapsedTime = 0
+
+	for attempt := 1; ; attempt++ {
+		err := client.Failover()
+		if err == nil {
+			client.reregister()
+			return nil
+		}
+		if errors.Is(err, ErrNotConnected) || attempt >= options.MaxAttempts {
+			return err
+		}
+
+		delay := policy.NextBackOff()
+		client.Logger().Warnf("consul:client", "restart attempt %d/%d failed - %s, retrying in %s", attempt, options.MaxAttempts, err.Error(), delay.String())
+		select {
+		case <-quit:
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// reregister registers all attached components again
+func (client *Client) reregister() {
+	client.mutex.RLock()
+	registrants := append([]Registrant(nil), client.registrants...)
+	client.mutex.RUnlock()
+
+	for _, registrant := range registrants {
+		if err := registrant.Reregister(); err != nil {
+			client.Logger().Errorf("consul:client", "failed to re-register after restart - %s", err.Error())
+		}
+	}
+}
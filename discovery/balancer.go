@@ -0,0 +1,149 @@
+package discovery
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Balancer selects instance to send request to
+type Balancer interface {
+	Pick(instances []Instance) (Instance, bool)
+}
+
+// RoundRobinBalancer selects instances one after another
+type RoundRobinBalancer struct {
+	mutex sync.Mutex
+	next  int
+}
+
+// NewRoundRobinBalancer creates new instance of round-robin balancer
+func NewRoundRobinBalancer() *RoundRobinBalancer {
+	return &RoundRobinBalancer{}
+}
+
+// Pick selects next instance
+func (balancer *RoundRobinBalancer) Pick(instances []Instance) (Instance, bool) {
+	if len(instances) == 0 {
+		return Instance{}, false
+	}
+	balancer.mutex.Lock()
+	defer balancer.mutex.Unlock()
+	instance := instances[balancer.next%len(instances)]
+	balancer.next++
+	return instance, true
+}
+
+// RandomBalancer selects random instance
+type RandomBalancer struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+// NewRandomBalancer creates new instance of random balancer
+func NewRandomBalancer() *RandomBalancer {
+	return &RandomBalancer{
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Pick selects random instance
+func (balancer *RandomBalancer) Pick(instances []Instance) (Instance, bool) {
+	if len(instances) == 0 {
+		return Instance{}, false
+	}
+	balancer.mutex.Lock()
+	defer balancer.mutex.Unlock()
+	return instances[balancer.random.Intn(len(instances))], true
+}
+
+// LeastRecentlyUsedBalancer selects instance which was not selected for the longest time
+type LeastRecentlyUsedBalancer struct {
+	mutex    sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+// NewLeastRecentlyUsedBalancer creates new instance of least-recently-used balancer
+func NewLeastRecentlyUsedBalancer() *LeastRecentlyUsedBalancer {
+	return &LeastRecentlyUsedBalancer{
+		lastUsed: make(map[string]time.Time),
+	}
+}
+
+// Pick selects least recently used instance (never used instances go first)
+func (balancer *LeastRecentlyUsedBalancer) Pick(instances []Instance) (Instance, bool) {
+	if len(instances) == 0 {
+		return Instance{}, false
+	}
+	balancer.mutex.Lock()
+	defer balancer.mutex.Unlock()
+
+	selected := 0
+	for index := range instances {
+		if balancer.lastUsed[instances[index].ID].Before(balancer.lastUsed[instances[selected].ID]) {
+			selected = index
+		}
+	}
+
+	now := time.Now()
+	balancer.lastUsed[instances[selected].ID] = now
+
+	if len(balancer.lastUsed) > 2*len(instances) {
+		known := make(map[string]struct{}, len(instances))
+		for _, instance := range instances {
+			known[instance.ID] = struct{}{}
+		}
+		for id := range balancer.lastUsed {
+			if _, ok := known[id]; !ok {
+				delete(balancer.lastUsed, id)
+			}
+		}
+	}
+	return instances[selected], true
+}
+
+// WeightedBalancer selects random instance, instances with higher passing weight are selected more often
+type WeightedBalancer struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+// NewWeightedBalancer creates new instance of weighted balancer
+func NewWeightedBalancer() *WeightedBalancer {
+	return &WeightedBalancer{
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Pick selects random instance according to its weight
+func (balancer *WeightedBalancer) Pick(instances []Instance) (Instance, bool) {
+	if len(instances) == 0 {
+		return Instance{}, false
+	}
+
+	total := 0
+	for _, instance := range instances {
+		total += instanceWeight(instance)
+	}
+
+	balancer.mutex.Lock()
+	pick := balancer.random.Intn(total)
+	balancer.mutex.Unlock()
+
+	for _, instance := range instances {
+		weight := instanceWeight(instance)
+		if pick < weight {
+			return instance, true
+		}
+		pick -= weight
+	}
+	return instances[len(instances)-1], true
+}
+
+// instanceWeight returns instance weight treating non-positive values as one
+func instanceWeight(instance Instance) int {
+	if instance.Weight <= 0 {
+		return 1
+	}
+	return instance.Weight
+}
@@ -0,0 +1,136 @@
+package discovery
+
+import (
+	"testing"
+)
+
+func TestBalancersWithoutInstances(t *testing.T) {
+	tests := []struct {
+		name     string
+		balancer Balancer
+	}{
+		{name: "round robin", balancer: NewRoundRobinBalancer()},
+		{name: "random", balancer: NewRandomBalancer()},
+		{name: "least recently used", balancer: NewLeastRecentlyUsedBalancer()},
+		{name: "weighted", balancer: NewWeightedBalancer()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if instance, ok := test.balancer.Pick(nil); ok {
+				t.Errorf("Pick() = %+v, want no instance", instance)
+			}
+		})
+	}
+}
+
+func TestBalancersSequence(t *testing.T) {
+	instances := []Instance{{ID: "first"}, {ID: "second"}, {ID: "third"}}
+
+	tests := []struct {
+		name      string
+		balancer  Balancer
+		instances []Instance
+		want      []string
+	}{
+		{
+			name:      "round robin",
+			balancer:  NewRoundRobinBalancer(),
+			instances: instances,
+			want:      []string{"first", "second", "third", "first", "second"},
+		},
+		{
+			name:      "least recently used",
+			balancer:  NewLeastRecentlyUsedBalancer(),
+			instances: instances,
+			want:      []string{"first", "second", "third", "first", "second"},
+		},
+		{
+			name:      "random with single instance",
+			balancer:  NewRandomBalancer(),
+			instances: instances[:1],
+			want:      []string{"first", "first"},
+		},
+		{
+			name:      "weighted with single instance",
+			balancer:  NewWeightedBalancer(),
+			instances: instances[:1],
+			want:      []string{"first", "first"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for index, want := range test.want {
+				instance, ok := test.balancer.Pick(test.instances)
+				if !ok || instance.ID != want {
+					t.Errorf("Pick() #%d = %q, want %q", index, instance.ID, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLeastRecentlyUsedBalancerPrefersNewInstances(t *testing.T) {
+	balancer := NewLeastRecentlyUsedBalancer()
+	balancer.Pick([]Instance{{ID: "first"}})
+	balancer.Pick([]Instance{{ID: "first"}})
+
+	instance, _ := balancer.Pick([]Instance{{ID: "first"}, {ID: "second"}})
+	if instance.ID != "second" {
+		t.Errorf("Pick() = %q, want never used %q", instance.ID, "second")
+	}
+}
+
+func TestDistributingBalancers(t *testing.T) {
+	tests := []struct {
+		name      string
+		balancer  Balancer
+		instances []Instance
+		want      string
+	}{
+		{
+			name:      "weighted prefers heavier instance",
+			balancer:  NewWeightedBalancer(),
+			instances: []Instance{{ID: "light", Weight: 1}, {ID: "heavy", Weight: 99}},
+			want:      "heavy",
+		},
+		{
+			name:      "weighted treats non-positive weight as one",
+			balancer:  NewWeightedBalancer(),
+			instances: []Instance{{ID: "negative", Weight: -5}, {ID: "heavy", Weight: 50}},
+			want:      "heavy",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selections := make(map[string]int)
+			for index := 0; index < 1000; index++ {
+				instance, ok := test.balancer.Pick(test.instances)
+				if !ok {
+					t.Fatal("Pick() returned no instance")
+				}
+				selections[instance.ID]++
+			}
+			for id, count := range selections {
+				if id != test.want && count >= selections[test.want] {
+					t.Errorf("instance %q was selected %d times, more than %q (%d times)", id, count, test.want, selections[test.want])
+				}
+			}
+		})
+	}
+}
+
+func TestRandomBalancerPicksKnownInstances(t *testing.T) {
+	instances := []Instance{{ID: "first"}, {ID: "second"}, {ID: "third"}}
+	known := map[string]bool{"first": true, "second": true, "third": true}
+	balancer := NewRandomBalancer()
+
+	for index := 0; index < 100; index++ {
+		instance, ok := balancer.Pick(instances)
+		if !ok || !known[instance.ID] {
+			t.Fatalf("Pick() = %q, want one of the instances", instance.ID)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/watcher"
+)
+
+var (
+	// ErrClientNotSpecified is returned when resolver is created without Consul client
+	ErrClientNotSpecified = errors.New("consul client is not specified")
+	// ErrEmptyService is returned when resolver is created without service name
+	ErrEmptyService = errors.New("service cannot be empty")
+	// ErrNoInstances is returned when there are no healthy instances of the service
+	ErrNoInstances = errors.New("there are no healthy instances of the service")
+)
+
+// Instance represents structure of service instance
+type Instance struct {
+	ID      string
+	Service string
+	Node    string
+	Address string
+	Port    int
+	Tags    []string
+	Meta    map[string]string
+	Weight  int
+}
+
+// HostPort returns host:port string for instance
+func (instance Instance) HostPort() string {
+	return net.JoinHostPort(instance.Address, strconv.Itoa(instance.Port))
+}
+
+// Resolver represents structure of service instances resolver
+type Resolver struct {
+	mutex      sync.RWMutex
+	client     *client.Client
+	service    string
+	tags       []string
+	meta       map[string]string
+	dataCenter string
+	balancer   Balancer
+
+	instances    []Instance
+	readyChannel chan struct{}
+	ready        bool
+	cancel       context.CancelFunc
+	doneChannel  chan struct{}
+}
+
+// Options represents structure of resolver options
+type Options struct {
+	Client     *client.Client
+	Service    string            // Name of the service to resolve
+	Tags       []string          // Only instances having all tags are resolved
+	Meta       map[string]string // Only instances having all meta values are resolved
+	DataCenter string            // Datacenter to resolve instances in | Defaults to datacenter of the agent
+	Balancer   Balancer          // Balancer used by Next | Defaults to round-robin
+}
+
+// NewResolver creates new instance of resolver
+func NewResolver(options Options) (*Resolver, error) {
+	if options.Client == nil {
+		return nil, ErrClientNotSpecified
+	}
+	if options.Service == "" {
+		return nil, ErrEmptyService
+	}
+
+	resolver := &Resolver{
+		client:       options.Client,
+		service:      options.Service,
+		tags:         options.Tags,
+		meta:         options.Meta,
+		dataCenter:   options.DataCenter,
+		balancer:     options.Balancer,
+		readyChannel: make(chan struct{}),
+	}
+
+	if resolver.balancer == nil {
+		resolver.balancer = NewRoundRobinBalancer()
+	}
+
+	return resolver, nil
+}
+
+// Service returns name of the resolved service
+func (resolver *Resolver) Service() string {
+	return resolver.service
+}
+
+// Resolve queries healthy instances once, without keeping them current
+func (resolver *Resolver) Resolve() ([]Instance, error) {
+	apiClient, err := resolver.client.APIClientE()
+	if err != nil {
+		return nil, err
+	}
+	entries, _, err := apiClient.Health().ServiceMultipleTags(
+		resolver.service,
+		resolver.tags,
+		true,
+		&consulAPI.QueryOptions{Datacenter: resolver.dataCenter},
+	)
+	if err != nil {
+		return nil, err
+	}
+	instances := resolver.filter(entries)
+	resolver.update(instances)
+	return instances, nil
+}
+
+// Instances returns last known healthy instances
+func (resolver *Resolver) Instances() []Instance {
+	resolver.mutex.RLock()
+	defer resolver.mutex.RUnlock()
+	return resolver.instances
+}
+
+// Next returns instance selected by balancer, or ErrNoInstances
+func (resolver *Resolver) Next() (Instance, error) {
+	instances := resolver.Instances()
+	if len(instances) == 0 {
+		return Instance{}, ErrNoInstances
+	}
+	instance, ok := resolver.balancer.Pick(instances)
+	if !ok {
+		return Instance{}, ErrNoInstances
+	}
+	return instance, nil
+}
+
+// Ready returns channel which is closed once instances are resolved for the first time
+func (resolver *Resolver) Ready() <-chan struct{} {
+	return resolver.readyChannel
+}
+
+// WaitReady waits until instances are resolved for the first time or context is done
+func (resolver *Resolver) WaitReady(ctx context.Context) error {
+	select {
+	case <-resolver.readyChannel:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start keeps instances current using blocking queries, blocks until Stop is called
+func (resolver *Resolver) Start() {
+	resolver.Run(context.Background())
+}
+
+// Run keeps instances current using blocking queries, blocks until Stop is called or context is cancelled
+func (resolver *Resolver) Run(ctx context.Context) error {
+	updateChannel := make(chan []*consulAPI.ServiceEntry)
+	errorChannel := make(chan error)
+	serviceWatcher := &watcher.ServiceWatcher{
+		ConsulClient:  resolver.client,
+		Service:       resolver.service,
+		Tags:          resolver.tags,
+		DataCenter:    resolver.dataCenter,
+		UpdateChannel: updateChannel,
+		ErrorChannel:  errorChannel,
+	}
+
+	resolver.mutex.Lock()
+	if resolver.doneChannel != nil {
+		resolver.mutex.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	doneChannel := make(chan struct{})
+	resolver.cancel = cancel
+	resolver.doneChannel = doneChannel
+	resolver.mutex.Unlock()
+
+	defer func() {
+		resolver.mutex.Lock()
+		defer resolver.mutex.Unlock()
+		cancel()
+		close(doneChannel)
+		resolver.cancel = nil
+		resolver.doneChannel = nil
+	}()
+
+	resultChannel := make(chan error, 1)
+	go func() {
+		resultChannel <- serviceWatcher.Run(ctx)
+	}()
+
+	for {
+		select {
+		case err := <-resultChannel:
+			return err
+		case err := <-errorChannel:
+			resolver.client.Logger().With("service", resolver.service).Warnf("consul:discovery", "failed to resolve instances of `%s` - %s", resolver.service, err.Error())
+		case entries := <-updateChannel:
+			resolver.update(resolver.filter(entries))
+		}
+	}
+}
+
+// Stop stops keeping instances current
+func (resolver *Resolver) Stop() error {
+	resolver.mutex.Lock()
+	if resolver.doneChannel == nil {
+		resolver.mutex.Unlock()
+		return nil
+	}
+	resolver.cancel()
+	doneChannel := resolver.doneChannel
+	resolver.mutex.Unlock()
+	<-doneChannel
+	return nil
+}
+
+// update replaces known instances and marks resolver as ready
+func (resolver *Resolver) update(instances []Instance) {
+	resolver.mutex.Lock()
+	defer resolver.mutex.Unlock()
+	resolver.instances = instances
+	if !resolver.ready {
+		resolver.ready = true
+		close(resolver.readyChannel)
+	}
+}
+
+// filter converts service entries to instances, skipping ones without required meta values
+func (resolver *Resolver) filter(entries []*consulAPI.ServiceEntry) []Instance {
+	instances := make([]Instance, 0, len(entries))
+	for _, entry := range entries {
+		if !matchesMeta(entry.Service.Meta, resolver.meta) {
+			continue
+		}
+
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+
+		instances = append(instances, Instance{
+			ID:      entry.Service.ID,
+			Service: entry.Service.Service,
+			Node:    entry.Node.Node,
+			Address: address,
+			Port:    entry.Service.Port,
+			Tags:    entry.Service.Tags,
+			Meta:    entry.Service.Meta,
+			Weight:  entry.Service.Weights.Passing,
+		})
+	}
+	return instances
+}
+
+// matchesMeta checks whether meta contains all required values
+func matchesMeta(meta map[string]string, required map[string]string) bool {
+	for key, value := range required {
+		if meta[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/leads-su/consul/client"
+)
+
+// Transport represents structure of HTTP round tripper which routes `http://service-name/...` requests to live instances
+type Transport struct {
+	mutex     sync.Mutex
+	client    *client.Client
+	base      http.RoundTripper
+	resolvers map[string]*Resolver
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// NewTransport creates new instance of transport, base defaults to http.DefaultTransport
+func NewTransport(client *client.Client, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Transport{
+		client:    client,
+		base:      base,
+		resolvers: make(map[string]*Resolver),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// Register registers resolver with custom options (tags, meta, balancer) for its service and starts it
+func (transport *Transport) Register(resolver *Resolver) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if previous, ok := transport.resolvers[resolver.Service()]; ok {
+		go previous.Stop()
+	}
+	transport.resolvers[resolver.Service()] = resolver
+	go resolver.Run(transport.ctx)
+}
+
+// RoundTrip sends request to one of the live instances of the service named by request host
+func (transport *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	resolver, err := transport.resolver(request.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolver.WaitReady(request.Context()); err != nil {
+		return nil, err
+	}
+
+	instance, err := resolver.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing := request.Clone(request.Context())
+	outgoing.URL.Host = instance.HostPort()
+	outgoing.Host = ""
+	return transport.base.RoundTrip(outgoing)
+}
+
+// Close stops all resolvers
+func (transport *Transport) Close() {
+	transport.cancel()
+}
+
+// resolver returns resolver for the service, creating and starting it if needed
+func (transport *Transport) resolver(service string) (*Resolver, error) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	if resolver, ok := transport.resolvers[service]; ok {
+		return resolver, nil
+	}
+
+	resolver, err := NewResolver(Options{
+		Client:  transport.client,
+		Service: service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	transport.resolvers[service] = resolver
+	go resolver.Run(transport.ctx)
+	return resolver, nil
+}
@@ -0,0 +1,256 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+	"github.com/leads-su/consul/state"
+)
+
+var (
+	// ErrClientNotSpecified is returned when election is created without Consul client
+	ErrClientNotSpecified = errors.New("consul client is not specified")
+	// ErrEmptyKey is returned when election is created without lock key
+	ErrEmptyKey = errors.New("key cannot be empty")
+)
+
+// Election represents structure of leader election
+type Election struct {
+	mutex         sync.RWMutex
+	client        *client.Client
+	key           string
+	value         []byte
+	sessionTTL    time.Duration
+	lockDelay     time.Duration
+	retryInterval time.Duration
+	onElected     func()
+	onDemoted     func()
+
+	leader        bool
+	changeChannel chan bool
+	cancel        context.CancelFunc
+	doneChannel   chan struct{}
+}
+
+// Options represents structure of election options
+type Options struct {
+	Client        *client.Client
+	Key           string        // KV key used as a lock
+	Value         []byte        // Value stored in lock key while leader | Defaults to hostname
+	SessionTTL    time.Duration // Session TTL, session is renewed automatically | Defaults to 15 seconds
+	LockDelay     time.Duration // Time before released lock can be acquired again | Defaults to 15 seconds
+	RetryInterval time.Duration // Delay before next attempt after failure | Defaults to 5 seconds
+	OnElected     func()        // Called when leadership is acquired (must not block)
+	OnDemoted     func()        // Called when leadership is lost (must not block)
+}
+
+// NewElection creates new instance of leader election
+func NewElection(options Options) (*Election, error) {
+	if options.Client == nil {
+		return nil, ErrClientNotSpecified
+	}
+	if options.Key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	election := &Election{
+		client:        options.Client,
+		key:           options.Key,
+		value:         options.Value,
+		sessionTTL:    options.SessionTTL,
+		lockDelay:     options.LockDelay,
+		retryInterval: options.RetryInterval,
+		onElected:     options.OnElected,
+		onDemoted:     options.OnDemoted,
+		changeChannel: make(chan bool, 1),
+	}
+
+	if election.value == nil {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		election.value = []byte(hostname)
+	}
+
+	if election.sessionTTL == 0 {
+		election.sessionTTL = time.Duration(15) * time.Second
+	}
+
+	if election.lockDelay == 0 {
+		election.lockDelay = time.Duration(15) * time.Second
+	}
+
+	if election.retryInterval == 0 {
+		election.retryInterval = time.Duration(5) * time.Second
+	}
+
+	return election, nil
+}
+
+// IsLeader indicates whether this instance currently holds leadership
+func (election *Election) IsLeader() bool {
+	election.mutex.RLock()
+	defer election.mutex.RUnlock()
+	return election.leader
+}
+
+// Changes returns channel which receives leadership status every time it changes
+func (election *Election) Changes() <-chan bool {
+	return election.changeChannel
+}
+
+// Leader returns value stored in lock key by current leader (empty if there is no leader)
+func (election *Election) Leader() (string, error) {
+	apiClient, err := election.client.APIClientE()
+	if err != nil {
+		return "", err
+	}
+	pair, _, err := apiClient.KV().Get(election.key, nil)
+	if err != nil {
+		return "", err
+	}
+	if pair == nil || pair.Session == "" {
+		return "", nil
+	}
+	return string(pair.Value), nil
+}
+
+// Start starts participating in election, blocks until Stop is called
+func (election *Election) Start() {
+	election.Run(context.Background())
+}
+
+// Run starts participating in election, blocks until Stop is called or context is cancelled
+func (election *Election) Run(ctx context.Context) error {
+	election.mutex.Lock()
+	if election.doneChannel != nil {
+		election.mutex.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	doneChannel := make(chan struct{})
+	election.cancel = cancel
+	election.doneChannel = doneChannel
+	election.mutex.Unlock()
+
+	defer func() {
+		election.mutex.Lock()
+		defer election.mutex.Unlock()
+		cancel()
+		close(doneChannel)
+		election.cancel = nil
+		election.doneChannel = nil
+	}()
+
+	for {
+		if err := election.campaign(ctx); err != nil {
+			election.logger().Errorf("consul:election", "failed to acquire leadership for `%s` - %s", election.key, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(election.retryInterval):
+		}
+	}
+}
+
+// Stop stops participating in election, releasing leadership if it is held
+func (election *Election) Stop() error {
+	election.mutex.Lock()
+	if election.doneChannel == nil {
+		election.mutex.Unlock()
+		return nil
+	}
+	election.cancel()
+	doneChannel := election.doneChannel
+	election.mutex.Unlock()
+	<-doneChannel
+	return nil
+}
+
+// campaign acquires lock and holds it until it is lost or context is cancelled
+func (election *Election) campaign(ctx context.Context) error {
+	apiClient, err := election.client.APIClientE()
+	if err != nil {
+		return err
+	}
+	lock, err := apiClient.LockOpts(&consulAPI.LockOptions{
+		Key:            election.key,
+		Value:          election.value,
+		SessionName:    "election-" + election.key,
+		SessionTTL:     election.sessionTTL.String(),
+		LockDelay:      election.lockDelay,
+		MonitorRetries: 3,
+	})
+	if err != nil {
+		return err
+	}
+
+	lostChannel, err := lock.Lock(ctx.Done())
+	if err != nil {
+		return err
+	}
+	if lostChannel == nil {
+		return nil
+	}
+
+	election.elected()
+
+	select {
+	case <-lostChannel:
+		election.logger().Warnf("consul:election", "leadership for `%s` was lost", election.key)
+	case <-ctx.Done():
+	}
+
+	if err := lock.Unlock(); err != nil && err != consulAPI.ErrLockNotHeld {
+		election.logger().Warnf("consul:election", "failed to release lock `%s` - %s", election.key, err.Error())
+	}
+	election.demoted()
+	return nil
+}
+
+// elected marks instance as leader and notifies subscribers
+func (election *Election) elected() {
+	election.logger().Infof("consul:election", "acquired leadership for `%s`", election.key)
+	election.setLeader(true)
+	election.client.Broker().Publish(state.ConsulLeadershipAcquired)
+	if election.onElected != nil {
+		election.onElected()
+	}
+}
+
+// demoted marks instance as follower and notifies subscribers
+func (election *Election) demoted() {
+	election.logger().Infof("consul:election", "released leadership for `%s`", election.key)
+	election.setLeader(false)
+	election.client.Broker().Publish(state.ConsulLeadershipLost)
+	if election.onDemoted != nil {
+		election.onDemoted()
+	}
+}
+
+// setLeader updates leadership status and delivers it to change channel, replacing undelivered status
+func (election *Election) setLeader(leader bool) {
+	election.mutex.Lock()
+	election.leader = leader
+	election.mutex.Unlock()
+
+	select {
+	case <-election.changeChannel:
+	default:
+	}
+	election.changeChannel <- leader
+}
+
+// logger returns logger of the client with election key attached
+func (election *Election) logger() logging.Logger {
+	return election.client.Logger().With("key", election.key)
+}
@@ -0,0 +1,287 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+const (
+	StatusPassing  = consulAPI.HealthPassing
+	StatusWarning  = consulAPI.HealthWarning
+	StatusCritical = consulAPI.HealthCritical
+)
+
+// Checker checks health of the component, returns nil if component is healthy, Warning(err) if it is degraded
+// and any other error if it is critical
+type Checker func(ctx context.Context) error
+
+// warningError represents structure of error which marks component as degraded instead of critical
+type warningError struct {
+	err error
+}
+
+// Error returns error message
+func (err *warningError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns underlying error
+func (err *warningError) Unwrap() error {
+	return err.err
+}
+
+// Warning wraps error to report component as degraded (warning) instead of critical
+func Warning(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &warningError{err: err}
+}
+
+// ComponentResult represents structure of single component check result
+type ComponentResult struct {
+	Status string `json:"status"`
+	Output string `json:"output,omitempty"`
+}
+
+// Report represents structure of health report for all components
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentResult `json:"components"`
+}
+
+// IsCritical indicates whether at least one component is critical
+func (report Report) IsCritical() bool {
+	return report.Status == StatusCritical
+}
+
+// Output returns human readable summary of non-passing components
+func (report Report) Output() string {
+	names := make([]string, 0, len(report.Components))
+	for name, result := range report.Components {
+		if result.Status != StatusPassing {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("all %d components are passing", len(report.Components))
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		result := report.Components[name]
+		lines = append(lines, fmt.Sprintf("%s: %s - %s", name, result.Status, result.Output))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Registry represents structure of health checkers registry
+type Registry struct {
+	mutex    sync.RWMutex
+	checkers map[string]Checker
+	liveness map[string]Checker
+	gates    map[string]string
+	started  bool
+	timeout  time.Duration
+}
+
+// NewRegistry creates new instance of health registry, timeout limits execution time of every checker
+func NewRegistry(timeout time.Duration) *Registry {
+	if timeout == 0 {
+		timeout = time.Duration(5) * time.Second
+	}
+	return &Registry{
+		checkers: make(map[string]Checker),
+		liveness: make(map[string]Checker),
+		gates:    make(map[string]string),
+		timeout:  timeout,
+	}
+}
+
+// Register registers (or replaces) named checker
+func (registry *Registry) Register(name string, checker Checker) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.checkers[name] = checker
+}
+
+// Deregister removes named checker
+func (registry *Registry) Deregister(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.checkers, name)
+}
+
+// RegisterLiveness registers (or replaces) named liveness checker, which should fail only if process has to be restarted
+func (registry *Registry) RegisterLiveness(name string, checker Checker) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.liveness[name] = checker
+}
+
+// DeregisterLiveness removes named liveness checker
+func (registry *Registry) DeregisterLiveness(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.liveness, name)
+}
+
+// MarkNotReady marks application as not ready to receive traffic until MarkReady is called with the same name
+func (registry *Registry) MarkNotReady(name string, reason string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.gates[name] = reason
+}
+
+// MarkReady removes readiness gate previously set with MarkNotReady
+func (registry *Registry) MarkReady(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	delete(registry.gates, name)
+}
+
+// Timeout returns maximum execution time of every checker
+func (registry *Registry) Timeout() time.Duration {
+	return registry.timeout
+}
+
+// Started indicates whether application has been ready at least once
+func (registry *Registry) Started() bool {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.started
+}
+
+// Empty indicates whether there are no registered checkers
+func (registry *Registry) Empty() bool {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return len(registry.checkers) == 0
+}
+
+// Check runs all component checkers concurrently and returns report with the worst status
+func (registry *Registry) Check(ctx context.Context) Report {
+	registry.mutex.RLock()
+	checkers := copyCheckers(registry.checkers)
+	registry.mutex.RUnlock()
+	return registry.check(ctx, checkers)
+}
+
+// Liveness runs all liveness checkers concurrently and returns report with the worst status
+func (registry *Registry) Liveness(ctx context.Context) Report {
+	registry.mutex.RLock()
+	checkers := copyCheckers(registry.liveness)
+	registry.mutex.RUnlock()
+	return registry.check(ctx, checkers)
+}
+
+// Readiness returns report of component checkers combined with readiness gates, application is ready if report is not critical
+func (registry *Registry) Readiness(ctx context.Context) Report {
+	report := registry.Check(ctx)
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for name, reason := range registry.gates {
+		report.Components[name] = ComponentResult{Status: StatusCritical, Output: reason}
+		report.Status = StatusCritical
+	}
+	if !report.IsCritical() {
+		registry.started = true
+	}
+	return report
+}
+
+// Startup returns readiness report until application becomes ready for the first time, passing report afterwards
+func (registry *Registry) Startup(ctx context.Context) Report {
+	if registry.Started() {
+		return Report{Status: StatusPassing, Components: map[string]ComponentResult{}}
+	}
+	return registry.Readiness(ctx)
+}
+
+// check runs given checkers concurrently and returns report with the worst status
+func (registry *Registry) check(ctx context.Context, checkers map[string]Checker) Report {
+	ctx, cancel := context.WithTimeout(ctx, registry.timeout)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var group sync.WaitGroup
+	report := Report{
+		Status:     StatusPassing,
+		Components: make(map[string]ComponentResult, len(checkers)),
+	}
+
+	for name, checker := range checkers {
+		group.Add(1)
+		go func(name string, checker Checker) {
+			defer group.Done()
+			result := run(ctx, checker)
+			mutex.Lock()
+			defer mutex.Unlock()
+			report.Components[name] = result
+			if severity(result.Status) > severity(report.Status) {
+				report.Status = result.Status
+			}
+		}(name, checker)
+	}
+	group.Wait()
+	return report
+}
+
+// copyCheckers returns copy of checkers map
+func copyCheckers(checkers map[string]Checker) map[string]Checker {
+	result := make(map[string]Checker, len(checkers))
+	for name, checker := range checkers {
+		result[name] = checker
+	}
+	return result
+}
+
+// run executes checker, treating timeout and panic as critical failure
+func run(ctx context.Context, checker Checker) ComponentResult {
+	errorChannel := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				errorChannel <- fmt.Errorf("checker panicked - %v", recovered)
+			}
+		}()
+		errorChannel <- checker(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errorChannel:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	var warning *warningError
+	switch {
+	case err == nil:
+		return ComponentResult{Status: StatusPassing}
+	case errors.As(err, &warning):
+		return ComponentResult{Status: StatusWarning, Output: err.Error()}
+	default:
+		return ComponentResult{Status: StatusCritical, Output: err.Error()}
+	}
+}
+
+// severity returns numeric severity of the status
+func severity(status string) int {
+	switch status {
+	case StatusCritical:
+		return 2
+	case StatusWarning:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,250 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// passing is checker of healthy component
+func passing(ctx context.Context) error {
+	return nil
+}
+
+// degraded is checker of degraded component
+func degraded(ctx context.Context) error {
+	return Warning(errors.New("degraded"))
+}
+
+// failing is checker of failed component
+func failing(ctx context.Context) error {
+	return errors.New("failed")
+}
+
+// hanging is checker which does not respect context and never completes in time
+func hanging(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+// panicking is checker which panics
+func panicking(ctx context.Context) error {
+	panic("unexpected state")
+}
+
+func TestRegistryCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkers   map[string]Checker
+		want       string
+		components map[string]string
+	}{
+		{
+			name: "no checkers",
+			want: StatusPassing,
+		},
+		{
+			name:       "all passing",
+			checkers:   map[string]Checker{"database": passing, "cache": passing},
+			want:       StatusPassing,
+			components: map[string]string{"database": StatusPassing, "cache": StatusPassing},
+		},
+		{
+			name:       "warning",
+			checkers:   map[string]Checker{"database": passing, "cache": degraded},
+			want:       StatusWarning,
+			components: map[string]string{"database": StatusPassing, "cache": StatusWarning},
+		},
+		{
+			name:       "critical is worse than warning",
+			checkers:   map[string]Checker{"database": failing, "cache": degraded},
+			want:       StatusCritical,
+			components: map[string]string{"database": StatusCritical, "cache": StatusWarning},
+		},
+		{
+			name:       "timeout",
+			checkers:   map[string]Checker{"database": hanging, "cache": passing},
+			want:       StatusCritical,
+			components: map[string]string{"database": StatusCritical, "cache": StatusPassing},
+		},
+		{
+			name:       "panic",
+			checkers:   map[string]Checker{"database": panicking, "cache": passing},
+			want:       StatusCritical,
+			components: map[string]string{"database": StatusCritical, "cache": StatusPassing},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := NewRegistry(50 * time.Millisecond)
+			for name, checker := range test.checkers {
+				registry.Register(name, checker)
+			}
+
+			report := registry.Check(context.Background())
+			if report.Status != test.want {
+				t.Errorf("Status = %s, want %s", report.Status, test.want)
+			}
+			if report.IsCritical() != (test.want == StatusCritical) {
+				t.Errorf("IsCritical() = %t for status %s", report.IsCritical(), report.Status)
+			}
+			if len(report.Components) != len(test.components) {
+				t.Errorf("Components = %v, want %v", report.Components, test.components)
+			}
+			for name, status := range test.components {
+				if report.Components[name].Status != status {
+					t.Errorf("component %s status = %s, want %s", name, report.Components[name].Status, status)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryDeregister(t *testing.T) {
+	registry := NewRegistry(0)
+	if !registry.Empty() {
+		t.Fatal("Empty() = false for new registry")
+	}
+
+	registry.Register("database", failing)
+	registry.Deregister("database")
+	if !registry.Empty() {
+		t.Error("Empty() = false after checker was deregistered")
+	}
+	if report := registry.Check(context.Background()); report.Status != StatusPassing {
+		t.Errorf("Status = %s after checker was deregistered, want %s", report.Status, StatusPassing)
+	}
+}
+
+func TestWarning(t *testing.T) {
+	if Warning(nil) != nil {
+		t.Error("Warning(nil) is not nil")
+	}
+
+	cause := errors.New("degraded")
+	if err := Warning(cause); !errors.Is(err, cause) || err.Error() != cause.Error() {
+		t.Errorf("Warning() = %v, want wrapped %v", err, cause)
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	report := Report{
+		Status: StatusCritical,
+		Components: map[string]ComponentResult{
+			"database": {Status: StatusCritical, Output: "failed"},
+			"cache":    {Status: StatusWarning, Output: "degraded"},
+			"queue":    {Status: StatusPassing},
+		},
+	}
+	if want := "cache: warning - degraded\ndatabase: critical - failed"; report.Output() != want {
+		t.Errorf("Output() = %q, want %q", report.Output(), want)
+	}
+
+	report = Report{Status: StatusPassing, Components: map[string]ComponentResult{"queue": {Status: StatusPassing}}}
+	if want := "all 1 components are passing"; report.Output() != want {
+		t.Errorf("Output() = %q, want %q", report.Output(), want)
+	}
+}
+
+func TestRegistryReadiness(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkers map[string]Checker
+		gates    map[string]string
+		ready    []string
+		want     string
+	}{
+		{name: "no checkers and gates", want: StatusPassing},
+		{name: "degraded component is ready", checkers: map[string]Checker{"cache": degraded}, want: StatusWarning},
+		{name: "failed component is not ready", checkers: map[string]Checker{"database": failing}, want: StatusCritical},
+		{name: "gate", checkers: map[string]Checker{"cache": passing}, gates: map[string]string{"warmup": "loading cache"}, want: StatusCritical},
+		{name: "removed gate", gates: map[string]string{"warmup": "loading cache"}, ready: []string{"warmup"}, want: StatusPassing},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := NewRegistry(0)
+			for name, checker := range test.checkers {
+				registry.Register(name, checker)
+			}
+			for name, reason := range test.gates {
+				registry.MarkNotReady(name, reason)
+			}
+			for _, name := range test.ready {
+				registry.MarkReady(name)
+			}
+
+			report := registry.Readiness(context.Background())
+			if report.Status != test.want {
+				t.Errorf("Status = %s, want %s", report.Status, test.want)
+			}
+			for _, name := range test.ready {
+				if _, ok := report.Components[name]; ok {
+					t.Errorf("report contains removed gate %s", name)
+				}
+			}
+			for name, reason := range test.gates {
+				if contains(test.ready, name) {
+					continue
+				}
+				if result := report.Components[name]; result.Status != StatusCritical || result.Output != reason {
+					t.Errorf("gate %s = %+v, want critical with output %q", name, result, reason)
+				}
+			}
+			if registry.Started() != !report.IsCritical() {
+				t.Errorf("Started() = %t for readiness status %s", registry.Started(), report.Status)
+			}
+		})
+	}
+}
+
+func TestRegistryStartup(t *testing.T) {
+	registry := NewRegistry(0)
+	registry.MarkNotReady("warmup", "loading cache")
+
+	if report := registry.Startup(context.Background()); report.Status != StatusCritical || registry.Started() {
+		t.Fatalf("Startup() = %s before application was ready, want %s", report.Status, StatusCritical)
+	}
+
+	registry.MarkReady("warmup")
+	if report := registry.Startup(context.Background()); report.Status != StatusPassing || !registry.Started() {
+		t.Fatalf("Startup() = %s after application became ready, want %s", report.Status, StatusPassing)
+	}
+
+	registry.MarkNotReady("maintenance", "draining")
+	if report := registry.Startup(context.Background()); report.Status != StatusPassing {
+		t.Errorf("Startup() = %s after application was started, want %s", report.Status, StatusPassing)
+	}
+	if report := registry.Readiness(context.Background()); report.Status != StatusCritical {
+		t.Errorf("Readiness() = %s with gate, want %s", report.Status, StatusCritical)
+	}
+}
+
+func TestRegistryLiveness(t *testing.T) {
+	registry := NewRegistry(0)
+	registry.Register("database", failing)
+	registry.MarkNotReady("warmup", "loading cache")
+	registry.RegisterLiveness("deadlock", passing)
+
+	if report := registry.Liveness(context.Background()); report.Status != StatusPassing || len(report.Components) != 1 {
+		t.Errorf("Liveness() = %+v, want only passing liveness checkers", report)
+	}
+
+	registry.DeregisterLiveness("deadlock")
+	registry.RegisterLiveness("deadlock", failing)
+	if report := registry.Liveness(context.Background()); report.Status != StatusCritical {
+		t.Errorf("Liveness() = %s, want %s", report.Status, StatusCritical)
+	}
+}
+
+// contains checks whether names contain given name
+func contains(names []string, name string) bool {
+	for _, candidate := range names {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
@@ -1,33 +1,74 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"time"
 
-	"github.com/leads-su/logger"
+	"github.com/leads-su/consul/health"
 )
 
 // healthRouteResponse represents structure of health check response object
 type healthRouteResponse struct {
-	Status    bool   `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Timezone  string `json:"timezone"`
+	Status     bool                              `json:"status"`
+	Health     string                            `json:"health"`
+	Components map[string]health.ComponentResult `json:"components"`
+	Timestamp  string                            `json:"timestamp"`
+	Timezone   string                            `json:"timezone"`
 }
 
-// registerHealthRoute registers health check route for Consul agent
+// registerHealthRoute registers health check routes for Consul agent and orchestrator probes
 func (server *Server) registerHealthRoute() {
-	logger.Tracef("consul:http", "registered health check route")
-	http.HandleFunc("/health", server.handleHealthRequest)
+	server.logger().Tracef("consul:http", "registered health check routes")
+	server.mux.HandleFunc("/health", server.handleHealthRequest)
+	server.mux.HandleFunc("/health/live", server.handleLivenessRequest)
+	server.mux.HandleFunc("/health/ready", server.handleReadinessRequest)
+	server.mux.HandleFunc("/health/startup", server.handleStartupRequest)
 }
 
 // handleHealthRequest handles request to '/health' endpoint
 func (server *Server) handleHealthRequest(writer http.ResponseWriter, request *http.Request) {
-	writer.WriteHeader(http.StatusOK)
+	server.writeHealthReport(writer, request, (*health.Registry).Check)
+}
+
+// handleLivenessRequest handles request to '/health/live' endpoint
+func (server *Server) handleLivenessRequest(writer http.ResponseWriter, request *http.Request) {
+	server.writeHealthReport(writer, request, (*health.Registry).Liveness)
+}
+
+// handleReadinessRequest handles request to '/health/ready' endpoint
+func (server *Server) handleReadinessRequest(writer http.ResponseWriter, request *http.Request) {
+	server.writeHealthReport(writer, request, (*health.Registry).Readiness)
+}
+
+// handleStartupRequest handles request to '/health/startup' endpoint
+func (server *Server) handleStartupRequest(writer http.ResponseWriter, request *http.Request) {
+	server.writeHealthReport(writer, request, (*health.Registry).Startup)
+}
+
+// writeHealthReport builds report using registry of the server and writes it, responding with 503 when report is critical
+func (server *Server) writeHealthReport(writer http.ResponseWriter, request *http.Request, build func(*health.Registry, context.Context) health.Report) {
+	report := health.Report{
+		Status:     health.StatusPassing,
+		Components: map[string]health.ComponentResult{},
+	}
+	if server.Health != nil {
+		report = build(server.Health, request.Context())
+	}
+
+	statusCode := http.StatusOK
+	if report.IsCritical() {
+		statusCode = http.StatusServiceUnavailable
+	}
+
 	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
 	json.NewEncoder(writer).Encode(healthRouteResponse{
-		Status:    true,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Timezone:  "UTC",
+		Status:     !report.IsCritical(),
+		Health:     report.Status,
+		Components: report.Components,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Timezone:   "UTC",
 	})
 }
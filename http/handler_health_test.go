@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/leads-su/consul/health"
+)
+
+func TestHealthRequests(t *testing.T) {
+	passing := func(ctx context.Context) error { return nil }
+	degraded := func(ctx context.Context) error { return health.Warning(errors.New("degraded")) }
+	failing := func(ctx context.Context) error { return errors.New("failed") }
+
+	tests := []struct {
+		name       string
+		path       string
+		configure  func(registry *health.Registry)
+		wantCode   int
+		wantHealth string
+	}{
+		{name: "health without registry", path: "/health", wantCode: http.StatusOK, wantHealth: health.StatusPassing},
+		{name: "health passing", path: "/health", configure: func(registry *health.Registry) { registry.Register("database", passing) }, wantCode: http.StatusOK, wantHealth: health.StatusPassing},
+		{name: "health warning", path: "/health", configure: func(registry *health.Registry) { registry.Register("database", degraded) }, wantCode: http.StatusOK, wantHealth: health.StatusWarning},
+		{name: "health critical", path: "/health", configure: func(registry *health.Registry) { registry.Register("database", failing) }, wantCode: http.StatusServiceUnavailable, wantHealth: health.StatusCritical},
+		{name: "liveness ignores components", path: "/health/live", configure: func(registry *health.Registry) { registry.Register("database", failing) }, wantCode: http.StatusOK, wantHealth: health.StatusPassing},
+		{name: "liveness critical", path: "/health/live", configure: func(registry *health.Registry) { registry.RegisterLiveness("deadlock", failing) }, wantCode: http.StatusServiceUnavailable, wantHealth: health.StatusCritical},
+		{name: "readiness gate", path: "/health/ready", configure: func(registry *health.Registry) { registry.MarkNotReady("warmup", "loading cache") }, wantCode: http.StatusServiceUnavailable, wantHealth: health.StatusCritical},
+		{name: "startup before ready", path: "/health/startup", configure: func(registry *health.Registry) { registry.MarkNotReady("warmup", "loading cache") }, wantCode: http.StatusServiceUnavailable, wantHealth: health.StatusCritical},
+		{
+			name: "startup after ready",
+			path: "/health/startup",
+			configure: func(registry *health.Registry) {
+				registry.Readiness(context.Background())
+				registry.Register("database", failing)
+			},
+			wantCode:   http.StatusOK,
+			wantHealth: health.StatusPassing,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &Server{}
+			if test.configure != nil {
+				server.Health = health.NewRegistry(0)
+				test.configure(server.Health)
+			}
+			handlers := map[string]http.HandlerFunc{
+				"/health":         server.handleHealthRequest,
+				"/health/live":    server.handleLivenessRequest,
+				"/health/ready":   server.handleReadinessRequest,
+				"/health/startup": server.handleStartupRequest,
+			}
+
+			recorder := httptest.NewRecorder()
+			handlers[test.path](recorder, httptest.NewRequest(http.MethodGet, test.path, nil))
+			if recorder.Code != test.wantCode {
+				t.Errorf("status code = %d, want %d", recorder.Code, test.wantCode)
+			}
+
+			var response healthRouteResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("unable to decode response - %s", err)
+			}
+			if response.Health != test.wantHealth || response.Status != (test.wantCode == http.StatusOK) {
+				t.Errorf("response = %+v, want health %s", response, test.wantHealth)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package http
+
+import "net/http"
+
+// registerMetricsRoute registers route exposing metrics in Prometheus text format
+func (server *Server) registerMetricsRoute() {
+	server.logger().Tracef("consul:http", "registered metrics route")
+	server.mux.HandleFunc("/metrics", server.handleMetricsRequest)
+}
+
+// handleMetricsRequest handles request to '/metrics' endpoint
+func (server *Server) handleMetricsRequest(writer http.ResponseWriter, request *http.Request) {
+	server.Metrics.Handler().ServeHTTP(writer, request)
+}
@@ -1,45 +1,215 @@
 package http
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
-	"github.com/leads-su/logger"
+	"github.com/leads-su/consul/health"
+	"github.com/leads-su/consul/logging"
+	"github.com/leads-su/consul/metrics"
 )
 
 // Server represents structure of HTTP server
 type Server struct {
-	Enabled bool
-	Port    uint
+	Enabled      bool
+	Port         uint
+	Address      string           // Address bundled server binds to, may differ from advertised service host
+	ReadTimeout  time.Duration    // Maximum duration for reading entire request
+	WriteTimeout time.Duration    // Maximum duration before timing out writes of the response
+	IdleTimeout  time.Duration    // Maximum amount of time to wait for the next request on keep-alive connection
+	CertFile     string           // Path to TLS certificate, server uses HTTPS if both certificate and key are specified
+	KeyFile      string           // Path to TLS private key
+	Health       *health.Registry // Registry of application components reported by `/health` endpoint
+	Metrics      *metrics.Metrics // Metrics exposed by `/metrics` endpoint (endpoint responds with 404 if not set)
+	Logger       logging.Logger   // Logger used by the server | Defaults to logging.Default()
+
+	mutex  sync.Mutex
+	mux    *http.ServeMux
+	server *http.Server
+	done   chan struct{}
+}
+
+// Options represents structure of HTTP server options
+type Options struct {
+	Enabled      bool
+	Port         uint
+	Address      string        // Defaults to all interfaces
+	ReadTimeout  time.Duration // Defaults to 10 seconds
+	WriteTimeout time.Duration // Defaults to 10 seconds
+	IdleTimeout  time.Duration // Defaults to 60 seconds
+	CertFile     string
+	KeyFile      string
+	Health       *health.Registry // Registry of application components reported by `/health` endpoint
+	Metrics      *metrics.Metrics // Metrics exposed by `/metrics` endpoint
+	Logger       logging.Logger   // Defaults to logging.Default()
 }
 
 // NewServer creates new instance of HTTP server
 func NewServer(port uint, enabled bool) *Server {
-	server := &Server{
+	server, err := NewServerE(port, enabled)
+	if err != nil {
+		logging.Default().Errorf("consul:http", "failed to create http server - %s", err.Error())
+		os.Exit(1)
+	}
+	return server
+}
+
+// NewServerE creates new instance of HTTP server, or returns error if bundled server cannot be started
+func NewServerE(port uint, enabled bool) (*Server, error) {
+	return NewServerWithOptions(Options{
 		Enabled: enabled,
 		Port:    port,
+	})
+}
+
+// NewServerWithOptions creates new instance of HTTP server from options, or returns error if bundled server cannot be started
+func NewServerWithOptions(options Options) (*Server, error) {
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return nil, errors.New("both certificate and key files must be specified to enable tls")
 	}
-	server.registerRoutes()
-	return server
+
+	server := &Server{
+		Enabled:      options.Enabled,
+		Port:         options.Port,
+		Address:      options.Address,
+		ReadTimeout:  options.ReadTimeout,
+		WriteTimeout: options.WriteTimeout,
+		IdleTimeout:  options.IdleTimeout,
+		CertFile:     options.CertFile,
+		KeyFile:      options.KeyFile,
+		Health:       options.Health,
+		Metrics:      options.Metrics,
+		Logger:       options.Logger,
+		mux:          http.NewServeMux(),
+	}
+
+	if server.ReadTimeout == 0 {
+		server.ReadTimeout = time.Duration(10) * time.Second
+	}
+
+	if server.WriteTimeout == 0 {
+		server.WriteTimeout = time.Duration(10) * time.Second
+	}
+
+	if server.IdleTimeout == 0 {
+		server.IdleTimeout = time.Duration(60) * time.Second
+	}
+
+	if err := server.registerRoutes(); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// UsesTLS indicates whether bundled server serves HTTPS
+func (server *Server) UsesTLS() bool {
+	return server.CertFile != "" && server.KeyFile != ""
+}
+
+// Handler returns handler serving all routes of the package, so they can be mounted into existing router
+func (server *Server) Handler() http.Handler {
+	return server.mux
+}
+
+// Handle registers additional handler for the given pattern
+func (server *Server) Handle(pattern string, handler http.Handler) {
+	server.mux.Handle(pattern, handler)
+}
+
+// HandleFunc registers additional handler function for the given pattern
+func (server *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	server.mux.HandleFunc(pattern, handler)
 }
 
 // registerRoutes registers all routes needed for the package
-func (server *Server) registerRoutes() {
+func (server *Server) registerRoutes() error {
 	server.registerHealthRoute()
+	server.registerMetricsRoute()
+
+	return server.Start()
+}
+
+// Start starts bundled server if it is enabled in the configuration and not running yet
+func (server *Server) Start() error {
+	if !server.Enabled {
+		return nil
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.server != nil {
+		return nil
+	}
+
+	httpServer := &http.Server{
+		Handler:      server.mux,
+		ReadTimeout:  server.ReadTimeout,
+		WriteTimeout: server.WriteTimeout,
+		IdleTimeout:  server.IdleTimeout,
+	}
+
+	if server.UsesTLS() {
+		certificate, err := tls.LoadX509KeyPair(server.CertFile, server.KeyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load tls certificate - %w", err)
+		}
+		httpServer.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	}
+
+	endpoint := net.JoinHostPort(server.Address, fmt.Sprintf("%d", server.Port))
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return fmt.Errorf("unable to listen at %s - %w", endpoint, err)
+	}
+	done := make(chan struct{})
+	server.server = httpServer
+	server.done = done
 
-	server.enableBundledServer()
+	server.logger().Infof("consul:http", "starting http server at %s", endpoint)
+	go func() {
+		defer close(done)
+		var err error
+		if httpServer.TLSConfig != nil {
+			err = httpServer.ServeTLS(listener, "", "")
+		} else {
+			err = httpServer.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			server.logger().Errorf("consul:http", "http server stopped - %s", err.Error())
+		}
+	}()
+	return nil
 }
 
-// enableBundledServer enables bundled server if it is enabled in the configuration
-func (server *Server) enableBundledServer() {
-	if server.Enabled {
-		endpoint := fmt.Sprintf(":%d", server.Port)
-		logger.Infof("consul:http", "starting http server at %s", endpoint)
-		go func() {
-			err := http.ListenAndServe(endpoint, nil)
-			if err != nil {
-				logger.Fatalf("consul:http", "failed to create http server - %s", err.Error())
-			}
-		}()
+// Shutdown gracefully stops bundled server, waiting for active requests until context is done
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.mutex.Lock()
+	httpServer, done := server.server, server.done
+	server.server, server.done = nil, nil
+	server.mutex.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	server.logger().Infof("consul:http", "shutting down http server")
+	err := httpServer.Shutdown(ctx)
+	if err != nil {
+		httpServer.Close()
 	}
+	<-done
+	return err
+}
+
+// logger returns logger of the server, or default logger if it is not specified
+func (server *Server) logger() logging.Logger {
+	return logging.Or(server.Logger)
 }
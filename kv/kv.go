@@ -0,0 +1,236 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/state"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrKeyNotFound is returned when requested key does not exist
+var ErrKeyNotFound = errors.New("key not found")
+
+// Store represents structure of KV store bound to Consul client
+type Store struct {
+	client *client.Client
+	prefix string
+}
+
+// NewStore creates new instance of KV store
+func NewStore(client *client.Client) *Store {
+	return &Store{
+		client: client,
+	}
+}
+
+// WithPrefix returns new store with all keys namespaced under specified prefix
+func (store *Store) WithPrefix(prefix string) *Store {
+	return &Store{
+		client: store.client,
+		prefix: store.path(prefix),
+	}
+}
+
+// Prefix returns prefix of the store
+func (store *Store) Prefix() string {
+	return store.prefix
+}
+
+// Get retrieves value for specified key, or returns ErrKeyNotFound
+func (store *Store) Get(key string) (*Value, error) {
+	kv, err := store.kv()
+	if err != nil {
+		return nil, err
+	}
+	pair, _, err := kv.Get(store.path(key), nil)
+	if err != nil {
+		return nil, store.failed(err)
+	}
+	if pair == nil {
+		return nil, ErrKeyNotFound
+	}
+	return store.value(pair), nil
+}
+
+// List retrieves all values under specified prefix
+func (store *Store) List(prefix string) ([]*Value, error) {
+	kv, err := store.kv()
+	if err != nil {
+		return nil, err
+	}
+	pairs, _, err := kv.List(store.path(prefix), nil)
+	if err != nil {
+		return nil, store.failed(err)
+	}
+	values := make([]*Value, 0, len(pairs))
+	for _, pair := range pairs {
+		values = append(values, store.value(pair))
+	}
+	return values, nil
+}
+
+// Keys retrieves list of keys under specified prefix (up to separator if it is not empty)
+func (store *Store) Keys(prefix string, separator string) ([]string, error) {
+	kv, err := store.kv()
+	if err != nil {
+		return nil, err
+	}
+	keys, _, err := kv.Keys(store.path(prefix), separator, nil)
+	if err != nil {
+		return nil, store.failed(err)
+	}
+	for index, key := range keys {
+		keys[index] = store.relative(key)
+	}
+	return keys, nil
+}
+
+// Put stores raw value for specified key
+func (store *Store) Put(key string, value []byte) error {
+	kv, err := store.kv()
+	if err != nil {
+		return err
+	}
+	_, err = kv.Put(&consulAPI.KVPair{
+		Key:   store.path(key),
+		Value: value,
+	}, nil)
+	return store.failed(err)
+}
+
+// PutString stores string value for specified key
+func (store *Store) PutString(key string, value string) error {
+	return store.Put(key, []byte(value))
+}
+
+// PutInt stores integer value for specified key
+func (store *Store) PutInt(key string, value int64) error {
+	return store.Put(key, []byte(strconv.FormatInt(value, 10)))
+}
+
+// PutBool stores boolean value for specified key
+func (store *Store) PutBool(key string, value bool) error {
+	return store.Put(key, []byte(strconv.FormatBool(value)))
+}
+
+// PutDuration stores duration value for specified key
+func (store *Store) PutDuration(key string, value time.Duration) error {
+	return store.Put(key, []byte(value.String()))
+}
+
+// PutJSON encodes value as JSON and stores it for specified key
+func (store *Store) PutJSON(key string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return store.Put(key, encoded)
+}
+
+// PutYAML encodes value as YAML and stores it for specified key
+func (store *Store) PutYAML(key string, value interface{}) error {
+	encoded, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return store.Put(key, encoded)
+}
+
+// PutCAS stores value only if key was not modified since specified index (index 0 means key must not exist)
+func (store *Store) PutCAS(key string, value []byte, index uint64) (bool, error) {
+	kv, err := store.kv()
+	if err != nil {
+		return false, err
+	}
+	success, _, err := kv.CAS(&consulAPI.KVPair{
+		Key:         store.path(key),
+		Value:       value,
+		ModifyIndex: index,
+	}, nil)
+	return success, store.failed(err)
+}
+
+// Delete deletes specified key
+func (store *Store) Delete(key string) error {
+	kv, err := store.kv()
+	if err != nil {
+		return err
+	}
+	_, err = kv.Delete(store.path(key), nil)
+	return store.failed(err)
+}
+
+// DeleteCAS deletes key only if it was not modified since specified index
+func (store *Store) DeleteCAS(key string, index uint64) (bool, error) {
+	kv, err := store.kv()
+	if err != nil {
+		return false, err
+	}
+	success, _, err := kv.DeleteCAS(&consulAPI.KVPair{
+		Key:         store.path(key),
+		ModifyIndex: index,
+	}, nil)
+	return success, store.failed(err)
+}
+
+// DeleteTree deletes all keys under specified prefix
+func (store *Store) DeleteTree(prefix string) error {
+	kv, err := store.kv()
+	if err != nil {
+		return err
+	}
+	_, err = kv.DeleteTree(store.path(prefix), nil)
+	return store.failed(err)
+}
+
+// kv returns KV endpoint of currently active API client or ErrNotConnected if client is disconnected
+func (store *Store) kv() (*consulAPI.KV, error) {
+	apiClient, err := store.client.APIClientE()
+	if err != nil {
+		return nil, err
+	}
+	return apiClient.KV(), nil
+}
+
+// path returns full path for key
+func (store *Store) path(key string) string {
+	if store.prefix == "" {
+		return key
+	}
+	return strings.TrimSuffix(store.prefix, "/") + "/" + strings.TrimPrefix(key, "/")
+}
+
+// relative returns key relative to store prefix
+func (store *Store) relative(key string) string {
+	if store.prefix == "" {
+		return key
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(key, strings.TrimSuffix(store.prefix, "/")), "/")
+}
+
+// value wraps KV pair into value
+func (store *Store) value(pair *consulAPI.KVPair) *Value {
+	return &Value{
+		key:  store.relative(pair.Key),
+		pair: pair,
+	}
+}
+
+// failed requests client restart if error is caused by connectivity failure, responses rejected by Consul
+// (including HTTP-level rejections such as 400, 403 or 413) are returned as is
+func (store *Store) failed(err error) error {
+	if err == nil {
+		return nil
+	}
+	if client.IsConnectivityError(err) {
+		store.client.Logger().Errorf("consul:kv", "request to consul failed - %s", err.Error())
+		state.Publish(store.client.Broker(), state.RestartRequested{Source: "consul:kv", Err: err})
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+// maxTransactionOperations is the maximum number of operations allowed by Consul in single transaction
+const maxTransactionOperations = 64
+
+// ErrTooManyOperations is returned when transaction exceeds Consul operations limit
+var ErrTooManyOperations = errors.New("transaction cannot contain more than 64 operations")
+
+// TransactionError represents structure of transaction rollback error
+type TransactionError struct {
+	Errors consulAPI.TxnErrors
+}
+
+// Error returns error message
+func (err *TransactionError) Error() string {
+	messages := make([]string, 0, len(err.Errors))
+	for _, operationError := range err.Errors {
+		messages = append(messages, fmt.Sprintf("operation %d: %s", operationError.OpIndex, operationError.What))
+	}
+	return "transaction rolled back - " + strings.Join(messages, "; ")
+}
+
+// Transaction represents structure of atomic KV transaction
+type Transaction struct {
+	store      *Store
+	operations consulAPI.TxnOps
+}
+
+// Transaction creates new atomic transaction
+func (store *Store) Transaction() *Transaction {
+	return &Transaction{
+		store: store,
+	}
+}
+
+// Set sets value for key
+func (transaction *Transaction) Set(key string, value []byte) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVSet, Key: key, Value: value})
+}
+
+// CAS sets value for key only if it was not modified since specified index
+func (transaction *Transaction) CAS(key string, value []byte, index uint64) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVCAS, Key: key, Value: value, Index: index})
+}
+
+// Get retrieves value for key (transaction fails if key does not exist)
+func (transaction *Transaction) Get(key string) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVGet, Key: key})
+}
+
+// CheckIndex fails transaction if key was modified since specified index
+func (transaction *Transaction) CheckIndex(key string, index uint64) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVCheckIndex, Key: key, Index: index})
+}
+
+// CheckNotExists fails transaction if key exists
+func (transaction *Transaction) CheckNotExists(key string) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVCheckNotExists, Key: key})
+}
+
+// Delete deletes key
+func (transaction *Transaction) Delete(key string) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVDelete, Key: key})
+}
+
+// DeleteCAS deletes key only if it was not modified since specified index
+func (transaction *Transaction) DeleteCAS(key string, index uint64) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVDeleteCAS, Key: key, Index: index})
+}
+
+// DeleteTree deletes all keys under prefix
+func (transaction *Transaction) DeleteTree(prefix string) *Transaction {
+	return transaction.add(&consulAPI.KVTxnOp{Verb: consulAPI.KVDeleteTree, Key: prefix})
+}
+
+// Commit executes all operations atomically and returns values produced by them
+func (transaction *Transaction) Commit() ([]*Value, error) {
+	if len(transaction.operations) > maxTransactionOperations {
+		return nil, ErrTooManyOperations
+	}
+
+	apiClient, err := transaction.store.client.APIClientE()
+	if err != nil {
+		return nil, err
+	}
+	success, response, _, err := apiClient.Txn().Txn(transaction.operations, nil)
+	if err != nil {
+		return nil, transaction.store.failed(err)
+	}
+	if !success {
+		return nil, &TransactionError{
+			Errors: response.Errors,
+		}
+	}
+
+	var values []*Value
+	for _, result := range response.Results {
+		if result.KV != nil {
+			values = append(values, transaction.store.value(result.KV))
+		}
+	}
+	return values, nil
+}
+
+// add adds operation to transaction, prefixing key with store prefix
+func (transaction *Transaction) add(operation *consulAPI.KVTxnOp) *Transaction {
+	operation.Key = transaction.store.path(operation.Key)
+	transaction.operations = append(transaction.operations, &consulAPI.TxnOp{
+		KV: operation,
+	})
+	return transaction
+}
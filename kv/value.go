@@ -0,0 +1,72 @@
+package kv
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Value represents structure of value stored in KV store
+type Value struct {
+	key  string
+	pair *consulAPI.KVPair
+}
+
+// Key returns key relative to store prefix
+func (value *Value) Key() string {
+	return value.key
+}
+
+// Pair returns underlying Consul KV pair
+func (value *Value) Pair() *consulAPI.KVPair {
+	return value.pair
+}
+
+// ModifyIndex returns index of the last modification (used for CAS operations)
+func (value *Value) ModifyIndex() uint64 {
+	return value.pair.ModifyIndex
+}
+
+// Bytes returns raw value
+func (value *Value) Bytes() []byte {
+	return value.pair.Value
+}
+
+// String returns value as string
+func (value *Value) String() string {
+	return string(value.pair.Value)
+}
+
+// Int returns value parsed as integer
+func (value *Value) Int() (int64, error) {
+	return strconv.ParseInt(value.trimmed(), 10, 64)
+}
+
+// Bool returns value parsed as boolean
+func (value *Value) Bool() (bool, error) {
+	return strconv.ParseBool(value.trimmed())
+}
+
+// Duration returns value parsed as duration
+func (value *Value) Duration() (time.Duration, error) {
+	return time.ParseDuration(value.trimmed())
+}
+
+// JSON decodes JSON value into target
+func (value *Value) JSON(target interface{}) error {
+	return json.Unmarshal(value.pair.Value, target)
+}
+
+// YAML decodes YAML value into target
+func (value *Value) YAML(target interface{}) error {
+	return yaml.Unmarshal(value.pair.Value, target)
+}
+
+// trimmed returns value as string without surrounding whitespace
+func (value *Value) trimmed() string {
+	return strings.TrimSpace(string(value.pair.Value))
+}
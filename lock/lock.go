@@ -0,0 +1,158 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+)
+
+var (
+	// ErrClientNotSpecified is returned when locker is created without Consul client
+	ErrClientNotSpecified = errors.New("consul client is not specified")
+	// ErrEmptyKey is returned when lock is requested without key
+	ErrEmptyKey = errors.New("key cannot be empty")
+	// ErrInvalidLimit is returned when semaphore is requested with non-positive limit
+	ErrInvalidLimit = errors.New("semaphore limit must be positive")
+)
+
+// Locker represents structure of distributed locks factory
+type Locker struct {
+	client           *client.Client
+	value            []byte
+	sessionTTL       time.Duration
+	lockDelay        time.Duration
+	monitorRetries   int
+	monitorRetryTime time.Duration
+	retryInterval    time.Duration
+}
+
+// Options represents structure of locker options
+type Options struct {
+	Client           *client.Client
+	Value            []byte        // Value stored in lock key while it is held
+	SessionTTL       time.Duration // Session TTL, session is renewed automatically | Defaults to 15 seconds
+	LockDelay        time.Duration // Time before released lock can be acquired again (mutex only) | Defaults to 15 seconds
+	MonitorRetries   int           // Number of retries when lock monitoring fails with transient error | Defaults to 3
+	MonitorRetryTime time.Duration // Delay between monitoring retries | Defaults to 2 seconds
+	RetryInterval    time.Duration // Delay before next acquisition attempt after failure | Defaults to 1 second
+}
+
+// NewLocker creates new instance of locker
+func NewLocker(options Options) (*Locker, error) {
+	if options.Client == nil {
+		return nil, ErrClientNotSpecified
+	}
+
+	locker := &Locker{
+		client:           options.Client,
+		value:            options.Value,
+		sessionTTL:       options.SessionTTL,
+		lockDelay:        options.LockDelay,
+		monitorRetries:   options.MonitorRetries,
+		monitorRetryTime: options.MonitorRetryTime,
+		retryInterval:    options.RetryInterval,
+	}
+
+	if locker.sessionTTL == 0 {
+		locker.sessionTTL = time.Duration(15) * time.Second
+	}
+
+	if locker.lockDelay == 0 {
+		locker.lockDelay = time.Duration(15) * time.Second
+	}
+
+	if locker.monitorRetries == 0 {
+		locker.monitorRetries = 3
+	}
+
+	if locker.monitorRetryTime == 0 {
+		locker.monitorRetryTime = time.Duration(2) * time.Second
+	}
+
+	if locker.retryInterval == 0 {
+		locker.retryInterval = time.Duration(1) * time.Second
+	}
+
+	return locker, nil
+}
+
+// Mutex represents structure of acquired distributed lock
+type Mutex struct {
+	key         string
+	lock        *consulAPI.Lock
+	lostChannel <-chan struct{}
+}
+
+// Lock acquires lock for the key, blocks until it is acquired or context is done
+func (locker *Locker) Lock(ctx context.Context, key string) (*Mutex, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+
+	for {
+		apiClient, err := locker.client.APIClientE()
+		if err != nil {
+			return nil, err
+		}
+		consulLock, err := apiClient.LockOpts(&consulAPI.LockOptions{
+			Key:              key,
+			Value:            locker.value,
+			SessionName:      "lock-" + key,
+			SessionTTL:       locker.sessionTTL.String(),
+			LockDelay:        locker.lockDelay,
+			MonitorRetries:   locker.monitorRetries,
+			MonitorRetryTime: locker.monitorRetryTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		lostChannel, err := consulLock.Lock(ctx.Done())
+		if err == nil && lostChannel == nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			return &Mutex{
+				key:         key,
+				lock:        consulLock,
+				lostChannel: lostChannel,
+			}, nil
+		}
+		if err == consulAPI.ErrLockConflict || err == consulAPI.ErrLockHeld {
+			return nil, err
+		}
+
+		locker.client.Logger().With("key", key).Warnf("consul:lock", "failed to acquire lock `%s`, retrying - %s", key, err.Error())
+		if err := locker.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Key returns key of the lock
+func (mutex *Mutex) Key() string {
+	return mutex.key
+}
+
+// Lost returns channel which is closed when lock is lost (e.g. session is invalidated)
+func (mutex *Mutex) Lost() <-chan struct{} {
+	return mutex.lostChannel
+}
+
+// Unlock releases lock and destroys its session
+func (mutex *Mutex) Unlock() error {
+	return mutex.lock.Unlock()
+}
+
+// wait waits for retry interval or until context is done
+func (locker *Locker) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(locker.retryInterval):
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+package lock
+
+import (
+	"context"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+// Semaphore represents structure of distributed counting semaphore
+type Semaphore struct {
+	locker *Locker
+	prefix string
+	limit  int
+}
+
+// Lease represents structure of acquired semaphore slot
+type Lease struct {
+	semaphore   *consulAPI.Semaphore
+	lostChannel <-chan struct{}
+}
+
+// Semaphore creates counting semaphore under the prefix which allows up to limit holders at the same time
+func (locker *Locker) Semaphore(prefix string, limit int) (*Semaphore, error) {
+	if prefix == "" {
+		return nil, ErrEmptyKey
+	}
+	if limit <= 0 {
+		return nil, ErrInvalidLimit
+	}
+	return &Semaphore{
+		locker: locker,
+		prefix: prefix,
+		limit:  limit,
+	}, nil
+}
+
+// Acquire acquires semaphore slot, blocks until it is acquired or context is done
+func (semaphore *Semaphore) Acquire(ctx context.Context) (*Lease, error) {
+	locker := semaphore.locker
+	for {
+		apiClient, err := locker.client.APIClientE()
+		if err != nil {
+			return nil, err
+		}
+		consulSemaphore, err := apiClient.SemaphoreOpts(&consulAPI.SemaphoreOptions{
+			Prefix:           semaphore.prefix,
+			Limit:            semaphore.limit,
+			Value:            locker.value,
+			SessionName:      "semaphore-" + semaphore.prefix,
+			SessionTTL:       locker.sessionTTL.String(),
+			MonitorRetries:   locker.monitorRetries,
+			MonitorRetryTime: locker.monitorRetryTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		lostChannel, err := consulSemaphore.Acquire(ctx.Done())
+		if err == nil && lostChannel == nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			return &Lease{
+				semaphore:   consulSemaphore,
+				lostChannel: lostChannel,
+			}, nil
+		}
+		if err == consulAPI.ErrSemaphoreConflict || err == consulAPI.ErrSemaphoreHeld {
+			return nil, err
+		}
+
+		locker.client.Logger().With("prefix", semaphore.prefix).Warnf("consul:lock", "failed to acquire semaphore `%s`, retrying - %s", semaphore.prefix, err.Error())
+		if err := locker.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Prefix returns prefix of the semaphore
+func (semaphore *Semaphore) Prefix() string {
+	return semaphore.prefix
+}
+
+// Limit returns maximum number of holders
+func (semaphore *Semaphore) Limit() int {
+	return semaphore.limit
+}
+
+// Lost returns channel which is closed when slot is lost (e.g. session is invalidated)
+func (lease *Lease) Lost() <-chan struct{} {
+	return lease.lostChannel
+}
+
+// Release releases semaphore slot and destroys its session
+func (lease *Lease) Release() error {
+	return lease.semaphore.Release()
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leads-su/logger"
+)
+
+// leadsLogger represents structure of adapter for github.com/leads-su/logger
+type leadsLogger struct {
+	fields string
+}
+
+// NewLeadsLogger creates logger writing to github.com/leads-su/logger, fields are appended to the message as key=value pairs
+func NewLeadsLogger() Logger {
+	return &leadsLogger{}
+}
+
+// Tracef logs message with trace level
+func (adapter *leadsLogger) Tracef(source string, format string, args ...interface{}) {
+	logger.Trace(source, adapter.message(format, args))
+}
+
+// Infof logs message with info level
+func (adapter *leadsLogger) Infof(source string, format string, args ...interface{}) {
+	logger.Info(source, adapter.message(format, args))
+}
+
+// Warnf logs message with warning level
+func (adapter *leadsLogger) Warnf(source string, format string, args ...interface{}) {
+	logger.Warn(source, adapter.message(format, args))
+}
+
+// Errorf logs message with error level
+func (adapter *leadsLogger) Errorf(source string, format string, args ...interface{}) {
+	logger.Error(source, adapter.message(format, args))
+}
+
+// With returns logger which adds field to every message
+func (adapter *leadsLogger) With(key string, value interface{}) Logger {
+	return &leadsLogger{
+		fields: strings.TrimSpace(fmt.Sprintf("%s %s=%v", adapter.fields, key, value)),
+	}
+}
+
+// message formats message and appends fields to it
+func (adapter *leadsLogger) message(format string, args []interface{}) string {
+	message := fmt.Sprintf(format, args...)
+	if adapter.fields == "" {
+		return message
+	}
+	return message + " " + adapter.fields
+}
@@ -0,0 +1,42 @@
+package logging
+
+import "sync"
+
+// Logger is implemented by loggers used by the package, source identifies package which produced the message (e.g. `consul:client`)
+type Logger interface {
+	Tracef(source string, format string, args ...interface{})
+	Infof(source string, format string, args ...interface{})
+	Warnf(source string, format string, args ...interface{})
+	Errorf(source string, format string, args ...interface{})
+	With(key string, value interface{}) Logger
+}
+
+var (
+	defaultMutex  sync.RWMutex
+	defaultLogger Logger = NewLeadsLogger()
+)
+
+// Default returns logger used when logger is not specified in options
+func Default() Logger {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces logger used when logger is not specified in options
+func SetDefault(logger Logger) {
+	if logger == nil {
+		logger = NewNopLogger()
+	}
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultLogger = logger
+}
+
+// Or returns logger if it is specified, default logger otherwise
+func Or(logger Logger) Logger {
+	if logger != nil {
+		return logger
+	}
+	return Default()
+}
@@ -0,0 +1,26 @@
+package logging
+
+// nopLogger represents structure of logger which discards all messages
+type nopLogger struct{}
+
+// NewNopLogger creates logger which discards all messages
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+// Tracef discards message
+func (nopLogger) Tracef(source string, format string, args ...interface{}) {}
+
+// Infof discards message
+func (nopLogger) Infof(source string, format string, args ...interface{}) {}
+
+// Warnf discards message
+func (nopLogger) Warnf(source string, format string, args ...interface{}) {}
+
+// Errorf discards message
+func (nopLogger) Errorf(source string, format string, args ...interface{}) {}
+
+// With returns the same logger
+func (logger nopLogger) With(key string, value interface{}) Logger {
+	return logger
+}
@@ -0,0 +1,59 @@
+//go:build go1.21
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// levelTrace is the slog level used for trace messages
+const levelTrace = slog.LevelDebug - 4
+
+// slogLogger represents structure of adapter for log/slog
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger creates logger writing to slog logger (default slog logger is used if nil), source is added as `component` attribute
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger: logger}
+}
+
+// Tracef logs message with trace level
+func (adapter *slogLogger) Tracef(source string, format string, args ...interface{}) {
+	adapter.log(levelTrace, source, format, args)
+}
+
+// Infof logs message with info level
+func (adapter *slogLogger) Infof(source string, format string, args ...interface{}) {
+	adapter.log(slog.LevelInfo, source, format, args)
+}
+
+// Warnf logs message with warning level
+func (adapter *slogLogger) Warnf(source string, format string, args ...interface{}) {
+	adapter.log(slog.LevelWarn, source, format, args)
+}
+
+// Errorf logs message with error level
+func (adapter *slogLogger) Errorf(source string, format string, args ...interface{}) {
+	adapter.log(slog.LevelError, source, format, args)
+}
+
+// With returns logger which adds attribute to every message
+func (adapter *slogLogger) With(key string, value interface{}) Logger {
+	return &slogLogger{logger: adapter.logger.With(key, value)}
+}
+
+// log formats and logs message if level is enabled
+func (adapter *slogLogger) log(level slog.Level, source string, format string, args []interface{}) {
+	ctx := context.Background()
+	if !adapter.logger.Enabled(ctx, level) {
+		return
+	}
+	adapter.logger.Log(ctx, level, fmt.Sprintf(format, args...), slog.String("component", source))
+}
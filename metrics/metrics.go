@@ -0,0 +1,232 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ErrNoGatherer is returned when registerer does not implement prometheus.Gatherer and gatherer is not specified
+var ErrNoGatherer = errors.New("gatherer must be specified when registerer does not implement prometheus.Gatherer")
+
+// Metrics represents structure of metrics collected by the package, all methods are safe to call on nil instance
+type Metrics struct {
+	registerer prometheus.Registerer
+	handler    http.Handler
+
+	probeDuration        *prometheus.HistogramVec
+	serverSelections     *prometheus.CounterVec
+	failovers            *prometheus.CounterVec
+	registrationFailures *prometheus.CounterVec
+	ttlUpdateDuration    *prometheus.HistogramVec
+	ttlUpdateFailures    *prometheus.CounterVec
+	watcherUpdates       *prometheus.CounterVec
+	watcherErrors        *prometheus.CounterVec
+	watcherLastIndex     *prometheus.GaugeVec
+	watcherQueryDuration *prometheus.HistogramVec
+	watcherQuiescence    *prometheus.HistogramVec
+}
+
+// Options represents structure of metrics options
+type Options struct {
+	Registerer prometheus.Registerer // Registerer metrics are registered with | Defaults to new registry
+	Gatherer   prometheus.Gatherer   // Gatherer used by Handler | Defaults to Registerer if it implements prometheus.Gatherer
+}
+
+// NewMetrics creates new instance of metrics registered in their own registry
+func NewMetrics() *Metrics {
+	// Registration in new registry cannot fail, as names of all collectors are unique
+	metrics, _ := NewMetricsWithOptions(Options{})
+	return metrics
+}
+
+// NewMetricsWithOptions creates new instance of metrics from options, or returns error if metrics cannot be registered
+func NewMetricsWithOptions(options Options) (*Metrics, error) {
+	if options.Registerer == nil {
+		registry := prometheus.NewRegistry()
+		options.Registerer = registry
+		if options.Gatherer == nil {
+			options.Gatherer = registry
+		}
+	}
+	if options.Gatherer == nil {
+		gatherer, ok := options.Registerer.(prometheus.Gatherer)
+		if !ok {
+			return nil, ErrNoGatherer
+		}
+		options.Gatherer = gatherer
+	}
+
+	metrics := &Metrics{
+		registerer: options.Registerer,
+		handler:    promhttp.HandlerFor(options.Gatherer, promhttp.HandlerOpts{}),
+
+		probeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "consul_client_probe_duration_seconds",
+			Help: "Round trip time of Consul server probes.",
+		}, []string{"server"}),
+		serverSelections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_client_server_selections_total",
+			Help: "Number of times server was selected as target server.",
+		}, []string{"server"}),
+		failovers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_client_failovers_total",
+			Help: "Number of failover attempts by result.",
+		}, []string{"result"}),
+		registrationFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_service_registration_failures_total",
+			Help: "Number of failed service registrations.",
+		}, []string{"service"}),
+		ttlUpdateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "consul_service_ttl_update_duration_seconds",
+			Help: "Latency of service TTL check updates.",
+		}, []string{"service", "status"}),
+		ttlUpdateFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_service_ttl_update_failures_total",
+			Help: "Number of failed service TTL check updates.",
+		}, []string{"service"}),
+		watcherUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_watcher_updates_total",
+			Help: "Number of updates delivered by watcher.",
+		}, []string{"watcher"}),
+		watcherErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "consul_watcher_errors_total",
+			Help: "Number of failed blocking queries.",
+		}, []string{"watcher"}),
+		watcherLastIndex: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "consul_watcher_last_index",
+			Help: "Index of the last update delivered by watcher.",
+		}, []string{"watcher"}),
+		watcherQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "consul_watcher_query_duration_seconds",
+			Help:    "Duration of blocking queries.",
+			Buckets: []float64{.01, .1, 1, 10, 60, 300, 600, 1800},
+		}, []string{"watcher"}),
+		watcherQuiescence: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "consul_watcher_quiescence_delay_seconds",
+			Help: "Delay between receiving result and delivering update caused by quiescence.",
+		}, []string{"watcher"}),
+	}
+
+	for _, collector := range metrics.collectors() {
+		if err := metrics.registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return metrics, nil
+}
+
+// Registerer returns registerer metrics are registered with, application collectors can be registered with it as well
+func (metrics *Metrics) Registerer() prometheus.Registerer {
+	if metrics == nil {
+		return nil
+	}
+	return metrics.registerer
+}
+
+// Handler returns HTTP handler serving metrics in Prometheus exposition format
+func (metrics *Metrics) Handler() http.Handler {
+	if metrics == nil {
+		return http.NotFoundHandler()
+	}
+	return metrics.handler
+}
+
+// ObserveProbe records round trip time of server probe
+func (metrics *Metrics) ObserveProbe(server string, rtt time.Duration) {
+	if metrics == nil {
+		return
+	}
+	metrics.probeDuration.WithLabelValues(server).Observe(rtt.Seconds())
+}
+
+// ServerSelected records selection of server
+func (metrics *Metrics) ServerSelected(server string) {
+	if metrics == nil {
+		return
+	}
+	metrics.serverSelections.WithLabelValues(server).Inc()
+}
+
+// Failover records failover attempt
+func (metrics *Metrics) Failover(err error) {
+	if metrics == nil {
+		return
+	}
+	metrics.failovers.WithLabelValues(result(err)).Inc()
+}
+
+// RegistrationFailed records failed service registration
+func (metrics *Metrics) RegistrationFailed(service string) {
+	if metrics == nil {
+		return
+	}
+	metrics.registrationFailures.WithLabelValues(service).Inc()
+}
+
+// ObserveTTLUpdate records TTL check update which took given duration
+func (metrics *Metrics) ObserveTTLUpdate(service string, status string, duration time.Duration, err error) {
+	if metrics == nil {
+		return
+	}
+	metrics.ttlUpdateDuration.WithLabelValues(service, status).Observe(duration.Seconds())
+	if err != nil {
+		metrics.ttlUpdateFailures.WithLabelValues(service).Inc()
+	}
+}
+
+// WatcherUpdated records update delivered by watcher
+func (metrics *Metrics) WatcherUpdated(watcher string, index uint64) {
+	if metrics == nil {
+		return
+	}
+	metrics.watcherUpdates.WithLabelValues(watcher).Inc()
+	metrics.watcherLastIndex.WithLabelValues(watcher).Set(float64(index))
+}
+
+// ObserveQuery records blocking query which took given duration
+func (metrics *Metrics) ObserveQuery(watcher string, duration time.Duration, err error) {
+	if metrics == nil {
+		return
+	}
+	metrics.watcherQueryDuration.WithLabelValues(watcher).Observe(duration.Seconds())
+	if err != nil {
+		metrics.watcherErrors.WithLabelValues(watcher).Inc()
+	}
+}
+
+// ObserveQuiescence records delay between receiving result and delivering update
+func (metrics *Metrics) ObserveQuiescence(watcher string, delay time.Duration) {
+	if metrics == nil {
+		return
+	}
+	metrics.watcherQuiescence.WithLabelValues(watcher).Observe(delay.Seconds())
+}
+
+// collectors returns all collectors of the package
+func (metrics *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		metrics.probeDuration,
+		metrics.serverSelections,
+		metrics.failovers,
+		metrics.registrationFailures,
+		metrics.ttlUpdateDuration,
+		metrics.ttlUpdateFailures,
+		metrics.watcherUpdates,
+		metrics.watcherErrors,
+		metrics.watcherLastIndex,
+		metrics.watcherQueryDuration,
+		metrics.watcherQuiescence,
+	}
+}
+
+// result returns value of `result` label for error
+func result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
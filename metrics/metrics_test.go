@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewMetricsWithOptions(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := NewMetricsWithOptions(Options{Registerer: registry}); err != nil {
+		t.Fatalf("unable to register metrics - %s", err)
+	}
+	gatherer := prometheus.NewRegistry()
+
+	tests := []struct {
+		name    string
+		options Options
+		wantErr bool
+	}{
+		{name: "own registry", options: Options{}},
+		{name: "custom registry", options: Options{Registerer: prometheus.NewRegistry()}},
+		{name: "registerer without gatherer", options: Options{Registerer: prometheus.WrapRegistererWith(prometheus.Labels{"app": "test"}, prometheus.NewRegistry())}, wantErr: true},
+		{name: "registerer with gatherer", options: Options{Registerer: prometheus.WrapRegistererWith(prometheus.Labels{"app": "test"}, gatherer), Gatherer: gatherer}},
+		{name: "already registered", options: Options{Registerer: registry}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metrics, err := NewMetricsWithOptions(test.options)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("NewMetricsWithOptions() error = %v, want error %t", err, test.wantErr)
+			}
+			if err == nil && metrics.Registerer() == nil {
+				t.Error("Registerer() = nil, want registerer")
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name   string
+		record func(metrics *Metrics)
+		metric string
+		want   string
+	}{
+		{
+			name:   "probe duration",
+			record: func(metrics *Metrics) { metrics.ObserveProbe("consul:8500", 10*time.Millisecond) },
+			metric: "consul_client_probe_duration_seconds",
+			want:   `consul_client_probe_duration_seconds_count{server="consul:8500"} 1`,
+		},
+		{
+			name: "server selections",
+			record: func(metrics *Metrics) {
+				metrics.ServerSelected("consul:8500")
+				metrics.ServerSelected("consul:8500")
+			},
+			metric: "consul_client_server_selections_total",
+			want:   `consul_client_server_selections_total{server="consul:8500"} 2`,
+		},
+		{
+			name: "failovers by result",
+			record: func(metrics *Metrics) {
+				metrics.Failover(nil)
+				metrics.Failover(failure)
+			},
+			metric: "consul_client_failovers_total",
+			want:   `consul_client_failovers_total{result="failure"} 1`,
+		},
+		{
+			name:   "registration failures",
+			record: func(metrics *Metrics) { metrics.RegistrationFailed("billing") },
+			metric: "consul_service_registration_failures_total",
+			want:   `consul_service_registration_failures_total{service="billing"} 1`,
+		},
+		{
+			name:   "failed ttl updates",
+			record: func(metrics *Metrics) { metrics.ObserveTTLUpdate("billing", "passing", time.Millisecond, failure) },
+			metric: "consul_service_ttl_update_failures_total",
+			want:   `consul_service_ttl_update_failures_total{service="billing"} 1`,
+		},
+		{
+			name: "watcher last index",
+			record: func(metrics *Metrics) {
+				metrics.WatcherUpdated("kv:application/", 10)
+				metrics.WatcherUpdated("kv:application/", 42)
+			},
+			metric: "consul_watcher_last_index",
+			want:   `consul_watcher_last_index{watcher="kv:application/"} 42`,
+		},
+		{
+			name:   "watcher errors",
+			record: func(metrics *Metrics) { metrics.ObserveQuery("kv:application/", time.Second, failure) },
+			metric: "consul_watcher_errors_total",
+			want:   `consul_watcher_errors_total{watcher="kv:application/"} 1`,
+		},
+		{
+			name:   "watcher quiescence",
+			record: func(metrics *Metrics) { metrics.ObserveQuiescence("kv:application/", time.Second) },
+			metric: "consul_watcher_quiescence_delay_seconds",
+			want:   `consul_watcher_quiescence_delay_seconds_sum{watcher="kv:application/"} 1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			metrics, err := NewMetricsWithOptions(Options{Registerer: registry})
+			if err != nil {
+				t.Fatalf("unable to register metrics - %s", err)
+			}
+			test.record(metrics)
+
+			families, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("unable to gather metrics - %s", err)
+			}
+			gathered := false
+			for _, family := range families {
+				gathered = gathered || family.GetName() == test.metric
+			}
+			if !gathered {
+				t.Fatalf("metric %s was not gathered", test.metric)
+			}
+
+			recorder := httptest.NewRecorder()
+			metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+			body, _ := ioutil.ReadAll(recorder.Body)
+			if !strings.Contains(string(body), test.want) {
+				t.Errorf("exposition does not contain %q:\n%s", test.want, body)
+			}
+		})
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var metrics *Metrics
+
+	metrics.ObserveProbe("consul:8500", time.Millisecond)
+	metrics.ServerSelected("consul:8500")
+	metrics.Failover(nil)
+	metrics.RegistrationFailed("billing")
+	metrics.ObserveTTLUpdate("billing", "passing", time.Millisecond, nil)
+	metrics.WatcherUpdated("kv:application/", 1)
+	metrics.ObserveQuery("kv:application/", time.Second, nil)
+	metrics.ObserveQuiescence("kv:application/", time.Second)
+
+	if metrics.Registerer() != nil {
+		t.Error("Registerer() of nil metrics is not nil")
+	}
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Handler() of nil metrics responded with %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+// Check represents structure of additional health check (exactly one of HTTP, TCP, GRPC, TTL or Alias must be set)
+type Check struct {
+	ID              string              // Check ID, prefixed with service ID, `ttl` and `http` are reserved | Required
+	Name            string              // Check name | Defaults to ID
+	Notes           string              // Human readable description
+	HTTP            string              // HTTP check: URL to query
+	Method          string              // HTTP check: request method | Defaults to GET
+	Header          map[string][]string // HTTP check: request headers
+	Body            string              // HTTP check: request body
+	TCP             string              // TCP check: host:port to connect to
+	GRPC            string              // gRPC check: host:port[/service] to query
+	GRPCUseTLS      bool                // gRPC check: use TLS connection
+	TTL             time.Duration       // TTL check: status must be updated through Service.UpdateCheckTTL within this period
+	AliasService    string              // Alias check: ID of the service which health is mirrored
+	AliasNode       string              // Alias check: node of the aliased service | Defaults to local agent
+	Interval        time.Duration       // Check interval | Defaults to service interval
+	Timeout         time.Duration       // Check timeout | Defaults to service timeout
+	TLSSkipVerify   bool                // HTTP / gRPC check: skip certificate verification
+	TLSServerName   string              // HTTP / gRPC check: server name used for certificate verification
+	DeregisterAfter time.Duration       // Service deregistration time in case this check is critical | Disabled by default
+}
+
+// computeID returns full check ID for the service
+func (check Check) computeID(serviceID string) string {
+	return serviceID + "-" + check.ID
+}
+
+// agentServiceCheck converts check to Consul agent check definition
+func (check Check) agentServiceCheck(serviceID string, interval time.Duration, timeout time.Duration) (*consulAPI.AgentServiceCheck, error) {
+	if check.ID == "" {
+		return nil, fmt.Errorf("%w: check id is required", ErrInvalidCheck)
+	}
+	checkID := check.computeID(serviceID)
+	if checkID == computeServiceTTLCheckID(serviceID) || checkID == computeServiceHttpCheckID(serviceID) {
+		return nil, fmt.Errorf("%w: check id `%s` is reserved for checks of the service", ErrInvalidCheck, check.ID)
+	}
+
+	types := 0
+	for _, defined := range []bool{check.HTTP != "", check.TCP != "", check.GRPC != "", check.TTL != 0, check.AliasService != ""} {
+		if defined {
+			types++
+		}
+	}
+	if types != 1 {
+		return nil, fmt.Errorf("%w: check `%s` must define exactly one of HTTP, TCP, GRPC, TTL or AliasService", ErrInvalidCheck, check.ID)
+	}
+
+	if check.Interval != 0 {
+		interval = check.Interval
+	}
+	if check.Timeout != 0 {
+		timeout = check.Timeout
+	}
+
+	name := check.Name
+	if name == "" {
+		name = check.ID
+	}
+
+	agentCheck := &consulAPI.AgentServiceCheck{
+		CheckID: checkID,
+		Name:    name,
+		Notes:   check.Notes,
+	}
+
+	if check.DeregisterAfter != 0 {
+		agentCheck.DeregisterCriticalServiceAfter = check.DeregisterAfter.String()
+	}
+
+	switch {
+	case check.TTL != 0:
+		agentCheck.TTL = check.TTL.String()
+		return agentCheck, nil
+	case check.AliasService != "":
+		agentCheck.AliasService = check.AliasService
+		agentCheck.AliasNode = check.AliasNode
+		return agentCheck, nil
+	case check.HTTP != "":
+		agentCheck.HTTP = check.HTTP
+		agentCheck.Method = check.Method
+		agentCheck.Header = check.Header
+		agentCheck.Body = check.Body
+	case check.TCP != "":
+		agentCheck.TCP = check.TCP
+	case check.GRPC != "":
+		agentCheck.GRPC = check.GRPC
+		agentCheck.GRPCUseTLS = check.GRPCUseTLS
+	}
+
+	agentCheck.Interval = interval.String()
+	agentCheck.Timeout = timeout.String()
+	agentCheck.TLSSkipVerify = check.TLSSkipVerify
+	agentCheck.TLSServerName = check.TLSServerName
+	return agentCheck, nil
+}
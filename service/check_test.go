@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAgentServiceCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		check    Check
+		wantID   string
+		wantTTL  string
+		wantHTTP string
+		wantErr  bool
+	}{
+		{name: "http check", check: Check{ID: "api", HTTP: "http://127.0.0.1:8080/status"}, wantID: "billing-api", wantHTTP: "http://127.0.0.1:8080/status"},
+		{name: "ttl check", check: Check{ID: "worker", TTL: 30 * time.Second}, wantID: "billing-worker", wantTTL: "30s"},
+		{name: "missing id", check: Check{TCP: "127.0.0.1:6379"}, wantErr: true},
+		{name: "no check type", check: Check{ID: "cache"}, wantErr: true},
+		{name: "several check types", check: Check{ID: "cache", TCP: "127.0.0.1:6379", TTL: time.Second}, wantErr: true},
+		{name: "reserved ttl id", check: Check{ID: "ttl", TTL: time.Second}, wantErr: true},
+		{name: "reserved http id", check: Check{ID: "http", HTTP: "http://127.0.0.1:8080/status"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			agentCheck, err := test.check.agentServiceCheck("billing", 10*time.Second, 5*time.Second)
+			if test.wantErr {
+				if !errors.Is(err, ErrInvalidCheck) {
+					t.Errorf("agentServiceCheck() error = %v, want %v", err, ErrInvalidCheck)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("agentServiceCheck() returned error - %s", err)
+			}
+			if agentCheck.CheckID != test.wantID || agentCheck.TTL != test.wantTTL || agentCheck.HTTP != test.wantHTTP {
+				t.Errorf("agentServiceCheck() = %+v, want id %q, ttl %q and http %q", agentCheck, test.wantID, test.wantTTL, test.wantHTTP)
+			}
+		})
+	}
+}
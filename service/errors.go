@@ -0,0 +1,14 @@
+package service
+
+import "errors"
+
+var (
+	// ErrClientNotSpecified is returned when service is created without Consul client
+	ErrClientNotSpecified = errors.New("consul client is not specified")
+	// ErrInvalidCheck is returned when check definition is invalid
+	ErrInvalidCheck = errors.New("invalid check definition")
+	// ErrCheckNotFound is returned when check with specified ID is not defined for the service
+	ErrCheckNotFound = errors.New("check not found")
+	// ErrNotRegistered is returned when operation requires service to be registered
+	ErrNotRegistered = errors.New("service is not registered in consul")
+)
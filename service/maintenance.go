@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// EnableMaintenance puts service into maintenance mode, so it is excluded from discovery until maintenance is disabled
+func (service *Service) EnableMaintenance(reason string) error {
+	serviceID := service.ID()
+	if serviceID == "" {
+		return ErrNotRegistered
+	}
+	agent, err := service.agent()
+	if err != nil {
+		return err
+	}
+	if err := agent.EnableServiceMaintenance(serviceID, reason); err != nil {
+		return err
+	}
+	service.health.MarkNotReady(service.maintenanceGate(), reason)
+	service.logger.With("service_id", serviceID).Infof("consul:service", "enabled maintenance mode for service `%s` - %s", serviceID, reason)
+	return nil
+}
+
+// DisableMaintenance takes service out of maintenance mode
+func (service *Service) DisableMaintenance() error {
+	serviceID := service.ID()
+	if serviceID == "" {
+		return ErrNotRegistered
+	}
+	agent, err := service.agent()
+	if err != nil {
+		return err
+	}
+	if err := agent.DisableServiceMaintenance(serviceID); err != nil {
+		return err
+	}
+	service.health.MarkReady(service.maintenanceGate())
+	service.logger.With("service_id", serviceID).Infof("consul:service", "disabled maintenance mode for service `%s`", serviceID)
+	return nil
+}
+
+// Drain puts service into maintenance mode, waits for grace period (or until context is done) so consumers stop routing to it,
+// then deregisters service and shuts down HTTP server
+func (service *Service) Drain(ctx context.Context, grace time.Duration) error {
+	if service.ID() != "" {
+		if err := service.EnableMaintenance("draining service before shutdown"); err != nil {
+			return err
+		}
+
+		service.logger.Infof("consul:service", "draining service `%s` for %s", service.name, grace.String())
+		timer := time.NewTimer(grace)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+
+		if err := service.Deregister(); err != nil {
+			return err
+		}
+	}
+
+	shutdownContext, cancel := context.WithTimeout(context.Background(), service.timeout)
+	defer cancel()
+	return service.server.Shutdown(shutdownContext)
+}
+
+// maintenanceGate returns name of readiness gate set while service is in maintenance mode
+func (service *Service) maintenanceGate() string {
+	return service.name + "-maintenance"
+}
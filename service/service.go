@@ -1,21 +1,25 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"runtime"
+	"sync"
 	"time"
 
 	consulAPI "github.com/hashicorp/consul/api"
 	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/health"
 	"github.com/leads-su/consul/http"
+	"github.com/leads-su/consul/logging"
 	"github.com/leads-su/consul/state"
-	"github.com/leads-su/logger"
 	"github.com/leads-su/version"
 )
 
 // Service represents structure of service configuration
 type Service struct {
+	mutex      sync.Mutex
 	client     *client.Client
 	name       string
 	extraName  string
@@ -25,6 +29,12 @@ type Service struct {
 	host       string
 	port       uint
 	httpServer bool
+	readiness  bool
+	checks     []Check
+	health     *health.Registry
+	server     *http.Server
+	logger     logging.Logger
+	serviceID  string
 
 	deregisterChannel chan bool
 	deregisterAfter   time.Duration
@@ -34,24 +44,73 @@ type Service struct {
 
 // Options represents structure of service options
 type Options struct {
-	Client          *client.Client
-	Name            string
-	ExtraName       string
-	ExtraMeta       map[string]string
-	Scheme          string
-	Host            string
-	Port            uint
-	HttpServer      bool
-	Tags            []string
-	DeregisterAfter time.Duration
-	Interval        time.Duration
-	Timeout         time.Duration
+	Client           *client.Client
+	Name             string
+	ExtraName        string
+	ExtraMeta        map[string]string
+	Scheme           string
+	Host             string
+	Port             uint
+	HttpServer       bool
+	BindAddress      string
+	HttpReadTimeout  time.Duration
+	HttpWriteTimeout time.Duration
+	HttpIdleTimeout  time.Duration
+	HttpCertFile     string
+	HttpKeyFile      string
+	ReadinessCheck   bool
+	Tags             []string
+	DeregisterAfter  time.Duration
+	Interval         time.Duration
+	Timeout          time.Duration
+	Checks           []Check
+	Health           *health.Registry
+	Logger           logging.Logger
 }
 
 // NewService creates new instance of Consul service
 func NewService(options Options) *Service {
+	service, err := NewServiceE(options)
+	if err != nil {
+		options.logger().Errorf("consul:service", "failed to create service `%s` - %s", options.Name, err.Error())
+		os.Exit(1)
+	}
+	return service
+}
+
+// NewServiceE creates new instance of Consul service, or returns error
+func NewServiceE(options Options) (*Service, error) {
+	if options.Client == nil {
+		return nil, ErrClientNotSpecified
+	}
+
+	for _, check := range options.Checks {
+		if _, err := check.agentServiceCheck(options.Name, options.Interval, options.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
 	options.Client.Broker().Publish(state.ConsulCreatingService)
-	http.NewServer(options.Port, options.HttpServer)
+	if options.Health == nil {
+		options.Health = health.NewRegistry(options.Timeout)
+	}
+
+	httpServer, err := http.NewServerWithOptions(http.Options{
+		Enabled:      options.HttpServer,
+		Port:         options.Port,
+		Address:      options.BindAddress,
+		ReadTimeout:  options.HttpReadTimeout,
+		WriteTimeout: options.HttpWriteTimeout,
+		IdleTimeout:  options.HttpIdleTimeout,
+		CertFile:     options.HttpCertFile,
+		KeyFile:      options.HttpKeyFile,
+		Health:       options.Health,
+		Metrics:      options.Client.Metrics(),
+		Logger:       options.logger(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	service := &Service{
 		name:            options.Name,
@@ -63,13 +122,21 @@ func NewService(options Options) *Service {
 		port:            options.Port,
 		client:          options.Client,
 		httpServer:      options.HttpServer,
+		readiness:       options.ReadinessCheck,
 		deregisterAfter: options.DeregisterAfter,
 		interval:        options.Interval,
 		timeout:         options.Timeout,
+		checks:          append([]Check(nil), options.Checks...),
+		health:          options.Health,
+		server:          httpServer,
+		logger:          options.logger(),
 	}
 
 	if service.scheme == "" {
 		service.scheme = "http"
+		if httpServer.UsesTLS() {
+			service.scheme = "https"
+		}
 	}
 
 	if service.host == "" {
@@ -88,8 +155,9 @@ func NewService(options Options) *Service {
 		service.timeout = time.Duration(30) * time.Second
 	}
 
+	options.Client.Attach(service)
 	options.Client.Broker().Publish(state.ConsulServiceCreated)
-	return service
+	return service, nil
 }
 
 // HostPort returns service host:port string
@@ -97,6 +165,28 @@ func (service *Service) HostPort() string {
 	return fmt.Sprintf("%s:%d", service.host, service.port)
 }
 
+// ID returns ID of the service, or empty string if it is not registered
+func (service *Service) ID() string {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	return service.serviceID
+}
+
+// Health returns registry of application components whose status is reported to Consul
+func (service *Service) Health() *health.Registry {
+	return service.health
+}
+
+// HTTPServer returns HTTP server of the service, its handler can be mounted into existing router
+func (service *Service) HTTPServer() *http.Server {
+	return service.server
+}
+
+// RegisterChecker registers named checker of application component
+func (service *Service) RegisterChecker(name string, checker health.Checker) {
+	service.health.Register(name, checker)
+}
+
 // FullPath returns service full path
 func (service *Service) FullPath() string {
 	return fmt.Sprintf("%s://%s", service.scheme, service.HostPort())
@@ -108,69 +198,150 @@ func (service *Service) Register() error {
 	if err != nil {
 		return err
 	}
+	if err := service.server.Start(); err != nil {
+		return err
+	}
+	service.mutex.Lock()
+	service.serviceID = configuration.ID
+	service.mutex.Unlock()
 	service.deregisterChannel = service.register(configuration)
 	service.client.Broker().Publish(state.ConsulServiceRegistered)
 	return nil
 }
 
+// Reregister registers service in Consul again (e.g. after client reconnected to another server), does nothing if service is not registered
+func (service *Service) Reregister() error {
+	if service.ID() == "" {
+		return nil
+	}
+	configuration, err := service.buildServiceConfiguration()
+	if err != nil {
+		return err
+	}
+	agent, err := service.agent()
+	if err != nil {
+		return err
+	}
+	if err := agent.ServiceRegister(configuration); err != nil {
+		return fmt.Errorf("unable to re-register service `%s` - %w", configuration.ID, err)
+	}
+	service.logger.With("service_id", configuration.ID).Infof("consul:service", "re-registered service `%s`", configuration.ID)
+	return nil
+}
+
 // Deregister deregisters service from Consul
 func (service *Service) Deregister() error {
 	if service.deregisterChannel == nil {
-		logger.Warnf("consul:service", "this service is not registered in consul")
+		service.logger.Warnf("consul:service", "this service is not registered in consul")
 		return nil
 	}
 	service.deregisterChannel <- true
 	<-service.deregisterChannel
+	service.deregisterChannel = nil
+	service.mutex.Lock()
+	service.serviceID = ""
+	service.mutex.Unlock()
+	service.health.MarkReady(service.maintenanceGate())
 	service.client.Broker().Publish(state.ConsulServiceDeregistered)
-	return nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), service.timeout)
+	defer cancel()
+	return service.server.Shutdown(ctx)
+}
+
+// Run registers service in Consul and blocks until context is cancelled, after which service is deregistered
+func (service *Service) Run(ctx context.Context) error {
+	if err := service.Register(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return service.Deregister()
 }
 
 // register handles de/registration process
 func (service *Service) register(registration *consulAPI.AgentServiceRegistration) chan bool {
+	log := service.logger.With("service_id", registration.ID)
+
 	registered := func(serviceID string) bool {
 		if serviceID == "" {
 			return false
 		}
-		services, err := service.client.APIClient().Agent().Services()
+		agent, err := service.agent()
+		if err != nil {
+			log.Errorf("consul:service", "cannot retrieve list of services - %s", err.Error())
+			return false
+		}
+		services, err := agent.Services()
 		if err != nil {
-			logger.Errorf("consul:service", "cannot retrieve list of services - %s", err.Error())
-			service.client.Broker().Publish(state.ConsulRestartRequested)
+			log.Errorf("consul:service", "cannot retrieve list of services - %s", err.Error())
+			if client.IsConnectivityError(err) {
+				state.Publish(service.client.Broker(), state.RestartRequested{Source: "consul:service", Err: err})
+			}
 			return false
 		}
 		return services[serviceID] != nil
 	}
 
 	register := func() string {
-		if err := service.client.APIClient().Agent().ServiceRegister(registration); err != nil {
-			logger.Errorf("consul:service", "failed to register service `%s` in consul - %s", registration.Name, err.Error())
-			service.client.Broker().Publish(state.ConsulRestartRequested)
+		if configuration, err := service.buildServiceConfiguration(); err == nil {
+			registration = configuration
+		}
+		agent, err := service.agent()
+		if err == nil {
+			err = agent.ServiceRegister(registration)
+		}
+		if err != nil {
+			log.Errorf("consul:service", "failed to register service `%s` in consul - %s", registration.Name, err.Error())
+			service.client.Metrics().RegistrationFailed(registration.Name)
+			if client.IsConnectivityError(err) {
+				state.Publish(service.client.Broker(), state.RegistrationFailed{ServiceID: registration.ID, Err: err})
+			}
 			return ""
 		}
 
-		logger.Tracef("consul:service", "registered `%s` with id `%s` at %s",
+		log.Tracef("consul:service", "registered `%s` with id `%s` at %s",
 			registration.Name,
 			registration.ID,
 			registration.Address,
 		)
 
 		for _, check := range registration.Checks {
-			logger.Tracef("consul:service", "registered check `%s` for service `%s`", check.CheckID, registration.ID)
+			log.Tracef("consul:service", "registered check `%s` for service `%s`", check.CheckID, registration.ID)
 		}
 		return registration.ID
 	}
 
 	deregister := func(serviceID string) {
-		logger.Tracef("consul:service", "de-registering service `%s` from consul", registration.Name)
-		err := service.client.APIClient().Agent().ServiceDeregister(serviceID)
+		log.Tracef("consul:service", "de-registering service `%s` from consul", registration.Name)
+		agent, err := service.agent()
+		if err == nil {
+			err = agent.ServiceDeregister(serviceID)
+		}
 		if err != nil {
-			logger.Errorf("consul:service", "Failed to deregister service - %s", err.Error())
+			log.Errorf("consul:service", "Failed to deregister service - %s", err.Error())
 		}
 	}
 
 	passTTL := func(serviceTTLCheckID string) {
-		err := service.client.APIClient().Agent().UpdateTTL(serviceTTLCheckID, time.Now().UTC().Format(time.RFC3339), consulAPI.HealthPassing)
+		status, output := consulAPI.HealthPassing, time.Now().UTC().Format(time.RFC3339)
+		if !service.health.Empty() {
+			ctx, cancel := context.WithTimeout(context.Background(), service.timeout)
+			report := service.health.Check(ctx)
+			cancel()
+			status, output = report.Status, report.Output()
+			if status != consulAPI.HealthPassing {
+				log.Warnf("consul:service", "service `%s` is %s - %s", registration.Name, status, output)
+			}
+		}
+
+		start := time.Now()
+		agent, err := service.agent()
+		if err == nil {
+			err = agent.UpdateTTL(serviceTTLCheckID, output, status)
+		}
+		service.client.Metrics().ObserveTTLUpdate(registration.Name, status, time.Since(start), err)
 		if err != nil {
-			logger.Errorf("consul:service", "Unable to pass TTL check for service with ID - %s", serviceTTLCheckID)
+			log.Errorf("consul:service", "Unable to pass TTL check for service with ID - %s", serviceTTLCheckID)
 		}
 	}
 
@@ -199,6 +370,87 @@ func (service *Service) register(registration *consulAPI.AgentServiceRegistratio
 	return deregisterChannel
 }
 
+// AddCheck adds (or replaces check with the same ID) health check, registering it immediately if service is registered
+func (service *Service) AddCheck(check Check) error {
+	service.mutex.Lock()
+	serviceID := service.serviceID
+	service.mutex.Unlock()
+
+	agentCheck, err := check.agentServiceCheck(serviceID, service.interval, service.timeout)
+	if err != nil {
+		return err
+	}
+
+	service.mutex.Lock()
+	replaced := false
+	for index := range service.checks {
+		if service.checks[index].ID == check.ID {
+			service.checks[index] = check
+			replaced = true
+		}
+	}
+	if !replaced {
+		service.checks = append(service.checks, check)
+	}
+	service.mutex.Unlock()
+
+	if serviceID == "" {
+		return nil
+	}
+
+	agent, err := service.agent()
+	if err != nil {
+		return err
+	}
+	return agent.CheckRegister(&consulAPI.AgentCheckRegistration{
+		ID:                agentCheck.CheckID,
+		Name:              agentCheck.Name,
+		Notes:             agentCheck.Notes,
+		ServiceID:         serviceID,
+		AgentServiceCheck: *agentCheck,
+	})
+}
+
+// RemoveCheck removes health check, deregistering it immediately if service is registered
+func (service *Service) RemoveCheck(checkID string) error {
+	service.mutex.Lock()
+	serviceID := service.serviceID
+	found := false
+	for index := range service.checks {
+		if service.checks[index].ID == checkID {
+			service.checks = append(service.checks[:index], service.checks[index+1:]...)
+			found = true
+			break
+		}
+	}
+	service.mutex.Unlock()
+
+	if !found {
+		return ErrCheckNotFound
+	}
+	if serviceID == "" {
+		return nil
+	}
+	agent, err := service.agent()
+	if err != nil {
+		return err
+	}
+	return agent.CheckDeregister(Check{ID: checkID}.computeID(serviceID))
+}
+
+// UpdateCheckTTL updates status of the custom TTL check
+func (service *Service) UpdateCheckTTL(checkID string, status string, output string) error {
+	serviceID := service.ID()
+	if serviceID == "" {
+		return ErrNotRegistered
+	}
+	agent, err := service.agent()
+	if err != nil {
+		return err
+	}
+	return agent.UpdateTTL(Check{ID: checkID}.computeID(serviceID), output, status)
+}
+
 // generateServiceID generates service ID from given data
 func (service *Service) generateServiceID() (string, error) {
 	hostname, err := os.Hostname()
@@ -233,14 +485,21 @@ func (service *Service) buildServiceConfiguration() (*consulAPI.AgentServiceRegi
 		serviceMeta[key] = value
 	}
 
+	service.mutex.Lock()
+	checks := append([]Check(nil), service.checks...)
+	service.mutex.Unlock()
+
 	serviceHealthChecks = append(serviceHealthChecks, &consulAPI.AgentServiceCheck{
 		CheckID:                        computeServiceTTLCheckID(serviceID),
-		TTL:                            (service.interval + time.Duration(5)).String(),
+		TTL:                            service.ttl().String(),
 		DeregisterCriticalServiceAfter: service.deregisterAfter.String(),
 	})
 
 	if service.httpServer && runtime.GOOS != "windows" {
 		checkURL := service.FullPath() + "/health"
+		if service.readiness {
+			checkURL += "/ready"
+		}
 		serviceHealthChecks = append(serviceHealthChecks, &consulAPI.AgentServiceCheck{
 			CheckID:  computeServiceHttpCheckID(serviceID),
 			HTTP:     checkURL,
@@ -248,6 +507,15 @@ func (service *Service) buildServiceConfiguration() (*consulAPI.AgentServiceRegi
 			Timeout:  service.timeout.String(),
 		})
 	}
+
+	for _, check := range checks {
+		serviceCheck, err := check.agentServiceCheck(serviceID, service.interval, service.timeout)
+		if err != nil {
+			return nil, err
+		}
+		serviceHealthChecks = append(serviceHealthChecks, serviceCheck)
+	}
+
 	return &consulAPI.AgentServiceRegistration{
 		ID:      serviceID,
 		Name:    service.name,
@@ -259,6 +527,20 @@ func (service *Service) buildServiceConfiguration() (*consulAPI.AgentServiceRegi
 	}, nil
 }
 
+// agent returns Consul agent endpoint of the client or ErrNotConnected if client is disconnected
+func (service *Service) agent() (*consulAPI.Agent, error) {
+	apiClient, err := service.client.APIClientE()
+	if err != nil {
+		return nil, err
+	}
+	return apiClient.Agent(), nil
+}
+
+// ttl returns TTL of the service check, leaving headroom for health checkers to complete before it expires
+func (service *Service) ttl() time.Duration {
+	return service.interval + service.health.Timeout()
+}
+
 // computeServiceTTLCheckID generate service TTL check ID
 func computeServiceTTLCheckID(serviceID string) string {
 	return serviceID + "-ttl"
@@ -268,3 +550,12 @@ func computeServiceTTLCheckID(serviceID string) string {
 func computeServiceHttpCheckID(serviceID string) string {
 	return serviceID + "-http"
 }
+
+// logger returns logger specified in options, or logger of the client
+func (options Options) logger() logging.Logger {
+	logger := options.Logger
+	if logger == nil && options.Client != nil {
+		logger = options.Client.Logger()
+	}
+	return logging.Or(logger).With("service", options.Name)
+}
@@ -1,7 +1,12 @@
 package state
 
+import "fmt"
+
+// State represents state of the Consul integration published through broker
+type State int
+
 const (
-	ConsulConfigurationPending = iota + consulIotaValue
+	ConsulConfigurationPending State = iota + consulIotaValue
 	ConsulConfigured
 
 	ConsulCreatingService
@@ -13,4 +18,46 @@ const (
 	ConsulStarted
 	ConsulShuttingDown
 	ConsulRestartRequested
+
+	ConsulFailoverStarted
+	ConsulFailoverCompleted
+	ConsulFailoverFailed
+
+	ConsulLeadershipAcquired
+	ConsulLeadershipLost
+
+	ConsulWatchUpdated
 )
+
+// stateNames contains names of all known states
+var stateNames = map[State]string{
+	ConsulConfigurationPending: "ConsulConfigurationPending",
+	ConsulConfigured:           "ConsulConfigured",
+	ConsulCreatingService:      "ConsulCreatingService",
+	ConsulServiceCreated:       "ConsulServiceCreated",
+	ConsulServiceRegistered:    "ConsulServiceRegistered",
+	ConsulServiceDeregistered:  "ConsulServiceDeregistered",
+	ConsulStarting:             "ConsulStarting",
+	ConsulStarted:              "ConsulStarted",
+	ConsulShuttingDown:         "ConsulShuttingDown",
+	ConsulRestartRequested:     "ConsulRestartRequested",
+	ConsulFailoverStarted:      "ConsulFailoverStarted",
+	ConsulFailoverCompleted:    "ConsulFailoverCompleted",
+	ConsulFailoverFailed:       "ConsulFailoverFailed",
+	ConsulLeadershipAcquired:   "ConsulLeadershipAcquired",
+	ConsulLeadershipLost:       "ConsulLeadershipLost",
+	ConsulWatchUpdated:         "ConsulWatchUpdated",
+}
+
+// String returns name of the state
+func (state State) String() string {
+	if name, ok := stateNames[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int(state))
+}
+
+// State returns state itself, so plain states can be used as events
+func (state State) State() State {
+	return state
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/leads-su/broker"
+)
+
+// Event is implemented by every message published by the package, State reports which state event corresponds to
+type Event interface {
+	State() State
+}
+
+// ServerSelected is published when client selects server to connect to
+type ServerSelected struct {
+	Server     string        // Address (host:port) of selected server
+	DataCenter string        // Datacenter of selected server
+	RTT        time.Duration // Round trip time measured while selecting server
+}
+
+// State returns ConsulConfigured
+func (event ServerSelected) State() State {
+	return ConsulConfigured
+}
+
+// String returns human readable representation of the event
+func (event ServerSelected) String() string {
+	return fmt.Sprintf("selected server %s (datacenter: %s) with ping of %s", event.Server, event.DataCenter, event.RTT)
+}
+
+// RestartRequested is published when Consul cannot be reached and connection has to be re-established
+type RestartRequested struct {
+	Source string // Package which requested restart
+	Err    error  // Error which caused the request
+}
+
+// State returns ConsulRestartRequested
+func (event RestartRequested) State() State {
+	return ConsulRestartRequested
+}
+
+// String returns human readable representation of the event
+func (event RestartRequested) String() string {
+	return fmt.Sprintf("restart requested by %s - %v", event.Source, event.Err)
+}
+
+// RegistrationFailed is published when service cannot be registered because agent is unreachable, it also requests restart
+type RegistrationFailed struct {
+	ServiceID string // ID of the service which failed to register
+	Err       error  // Error returned by Consul agent
+}
+
+// State returns ConsulRestartRequested
+func (event RegistrationFailed) State() State {
+	return ConsulRestartRequested
+}
+
+// String returns human readable representation of the event
+func (event RegistrationFailed) String() string {
+	return fmt.Sprintf("failed to register service %s - %v", event.ServiceID, event.Err)
+}
+
+// WatchUpdated is published when KV watcher receives new version of watched prefix
+type WatchUpdated struct {
+	Prefix string // Watched prefix
+	Index  uint64 // Index of received KV tree
+}
+
+// State returns ConsulWatchUpdated
+func (event WatchUpdated) State() State {
+	return ConsulWatchUpdated
+}
+
+// String returns human readable representation of the event
+func (event WatchUpdated) String() string {
+	return fmt.Sprintf("prefix %s updated at index %d", event.Prefix, event.Index)
+}
+
+// Publish publishes event to broker, events with payload are followed by their plain state,
+// so consumers of raw broker channel comparing messages with states keep receiving them
+func Publish(brk *broker.Broker, event Event) {
+	brk.Publish(event)
+	if _, plain := event.(State); !plain {
+		brk.Publish(event.State())
+	}
+}
+
+// Of returns event for broker message, converting plain integers published by custom brokers into State
+func Of(message interface{}) (Event, bool) {
+	switch value := message.(type) {
+	case Event:
+		return value, true
+	case int:
+		return State(value), true
+	}
+	return nil, false
+}
+
+// Is indicates whether broker message corresponds to given state
+func Is(message interface{}, state State) bool {
+	event, ok := Of(message)
+	return ok && event.State() == state
+}
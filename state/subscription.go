@@ -0,0 +1,89 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/leads-su/broker"
+)
+
+// Subscription represents structure of typed subscription to broker messages
+type Subscription struct {
+	sync.Mutex
+	broker      *broker.Broker
+	messages    chan interface{}
+	events      chan Event
+	quitChannel chan struct{}
+	doneChannel chan struct{}
+}
+
+// Subscribe subscribes to events published to broker (either created by client or passed to WithCustomBroker),
+// delivering only events of given states (or all events if no state is specified)
+func Subscribe(brk *broker.Broker, states ...State) *Subscription {
+	filter := make(map[State]struct{}, len(states))
+	for _, state := range states {
+		filter[state] = struct{}{}
+	}
+
+	subscription := &Subscription{
+		broker:      brk,
+		messages:    brk.Subscribe(),
+		events:      make(chan Event, 5),
+		quitChannel: make(chan struct{}),
+		doneChannel: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(subscription.doneChannel)
+		defer close(subscription.events)
+		// Plain states published by Publish after events with payload are consumed by counting
+		// outstanding events with payload per state, so interleaved events are not delivered twice
+		outstanding := make(map[State]int)
+		for {
+			select {
+			case <-subscription.quitChannel:
+				return
+			case message := <-subscription.messages:
+				event, ok := Of(message)
+				if !ok {
+					continue
+				}
+				if plain, isPlain := event.(State); !isPlain {
+					outstanding[event.State()]++
+				} else if outstanding[plain] > 0 {
+					outstanding[plain]--
+					continue
+				}
+				if _, accepted := filter[event.State()]; len(filter) > 0 && !accepted {
+					continue
+				}
+				select {
+				case subscription.events <- event:
+				case <-subscription.quitChannel:
+					return
+				}
+			}
+		}
+	}()
+	return subscription
+}
+
+// Events returns channel of events, it is closed once subscription is closed
+func (subscription *Subscription) Events() <-chan Event {
+	return subscription.events
+}
+
+// Close unsubscribes from broker and closes events channel
+func (subscription *Subscription) Close() {
+	subscription.Lock()
+	defer subscription.Unlock()
+
+	select {
+	case <-subscription.quitChannel:
+		return
+	default:
+	}
+
+	close(subscription.quitChannel)
+	<-subscription.doneChannel
+	subscription.broker.Unsubscribe(subscription.messages)
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/leads-su/broker"
+)
+
+// subscribed creates subscription to started broker, making sure it is registered before anything is published
+func subscribed(states ...State) (*broker.Broker, *Subscription) {
+	brk := broker.NewBroker()
+	subscription := Subscribe(brk, states...)
+	go brk.Start()
+	// Broker accepts next subscription only after it has registered the previous one
+	brk.Unsubscribe(brk.Subscribe())
+	return brk, subscription
+}
+
+func TestSubscription(t *testing.T) {
+	selected := ServerSelected{Server: "consul:8500", DataCenter: "dc1"}
+	restart := RestartRequested{Source: "consul:kv", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		states   []State
+		messages []interface{}
+		want     []Event
+	}{
+		{
+			name:     "plain state following its event is consumed",
+			messages: []interface{}{selected, ConsulConfigured},
+			want:     []Event{selected},
+		},
+		{
+			name:     "interleaved events",
+			messages: []interface{}{selected, restart, ConsulConfigured, ConsulRestartRequested},
+			want:     []Event{selected, restart},
+		},
+		{
+			name:     "plain state published after event",
+			messages: []interface{}{selected, ConsulConfigured, ConsulConfigured},
+			want:     []Event{selected, ConsulConfigured},
+		},
+		{
+			name:     "repeated plain states",
+			messages: []interface{}{ConsulConfigured, ConsulConfigured},
+			want:     []Event{ConsulConfigured, ConsulConfigured},
+		},
+		{
+			name:     "integer published by custom broker",
+			messages: []interface{}{int(ConsulStarted), "ignored"},
+			want:     []Event{ConsulStarted},
+		},
+		{
+			name:     "filtered by state",
+			states:   []State{ConsulRestartRequested},
+			messages: []interface{}{selected, restart, ConsulConfigured, ConsulRestartRequested},
+			want:     []Event{restart},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Shutting down state marks the end of published messages
+			var states []State
+			if len(test.states) > 0 {
+				states = append(states, test.states...)
+				states = append(states, ConsulShuttingDown)
+			}
+			brk, subscription := subscribed(states...)
+			defer brk.Stop()
+			defer subscription.Close()
+
+			for _, message := range append(test.messages, ConsulShuttingDown) {
+				brk.Publish(message)
+			}
+
+			var events []Event
+			timeout := time.After(time.Second)
+			for {
+				select {
+				case event := <-subscription.Events():
+					if event == ConsulShuttingDown {
+						if !reflect.DeepEqual(events, test.want) {
+							t.Errorf("Events() = %v, want %v", events, test.want)
+						}
+						return
+					}
+					events = append(events, event)
+				case <-timeout:
+					t.Fatalf("received %v before timeout, want %v", events, test.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+)
+
+// ServicesWatcher watches for changes in Consul service catalog
+type ServicesWatcher struct {
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	UpdateChannel     chan<- map[string][]string // Service names with their tags
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+}
+
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *ServicesWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *ServicesWatcher) Run(ctx context.Context) error {
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		services, meta, err := apiClient.Catalog().Services(options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return services, meta.LastIndex, nil
+	}
+
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		select {
+		case watcher.UpdateChannel <- result.(map[string][]string):
+			return true
+		case <-quitChannel:
+			return false
+		}
+	}
+
+	return watcher.run(ctx, source{
+		name:              "services",
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
+	}, nil, query, emit)
+}
+
+// Stop stops watching for changes
+func (watcher *ServicesWatcher) Stop() error {
+	return watcher.stop()
+}
+
+// NodesWatcher watches for changes in Consul node list
+type NodesWatcher struct {
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	UpdateChannel     chan<- []*consulAPI.Node
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+}
+
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *NodesWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *NodesWatcher) Run(ctx context.Context) error {
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		nodes, meta, err := apiClient.Catalog().Nodes(options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return nodes, meta.LastIndex, nil
+	}
+
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		select {
+		case watcher.UpdateChannel <- result.([]*consulAPI.Node):
+			return true
+		case <-quitChannel:
+			return false
+		}
+	}
+
+	return watcher.run(ctx, source{
+		name:              "nodes",
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
+	}, nil, query, emit)
+}
+
+// Stop stops watching for changes
+func (watcher *NodesWatcher) Stop() error {
+	return watcher.stop()
+}
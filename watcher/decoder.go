@@ -0,0 +1,347 @@
+package watcher
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+const (
+	// decoderKeyTag is the struct tag holding key (relative to parent prefix) field is decoded from
+	decoderKeyTag = "consul"
+	// decoderDefaultTag is the struct tag holding value used when key is missing
+	decoderDefaultTag = "default"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Validator is implemented by decoded configurations which are able to validate themselves
+type Validator interface {
+	Validate() error
+}
+
+// DecodeError represents structure of error occurred while decoding specific key
+type DecodeError struct {
+	Key   string
+	Field string
+	Err   error
+}
+
+// Error returns error message
+func (err DecodeError) Error() string {
+	return fmt.Sprintf("unable to decode key `%s` into field `%s` - %s", err.Key, err.Field, err.Err.Error())
+}
+
+// Unwrap returns underlying error
+func (err DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// decoder represents structure of KV tree decoder
+type decoder struct {
+	values map[string][]byte
+	errors []DecodeError
+}
+
+// Decode decodes KV pairs located under prefix into target (pointer to struct) using `consul` and `default` struct tags
+func Decode(prefix string, pairs consulAPI.KVPairs, target interface{}) []DecodeError {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return []DecodeError{{Err: fmt.Errorf("target must be a non-nil pointer to struct, got %T", target)}}
+	}
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	instance := &decoder{
+		values: make(map[string][]byte, len(pairs)),
+	}
+	for _, pair := range pairs {
+		if !strings.HasPrefix(pair.Key, prefix) || strings.HasSuffix(pair.Key, "/") {
+			continue
+		}
+		instance.values[strings.TrimPrefix(pair.Key, prefix)] = pair.Value
+	}
+
+	instance.decodeStruct("", "", value.Elem())
+	return instance.errors
+}
+
+// decodeStruct decodes all tagged fields of the structure
+func (instance *decoder) decodeStruct(key string, field string, value reflect.Value) {
+	structType := value.Type()
+	for index := 0; index < structType.NumField(); index++ {
+		structField := structType.Field(index)
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		fieldValue := value.Field(index)
+		fieldPath := joinFieldPath(field, structField.Name)
+		tag := structField.Tag.Get(decoderKeyTag)
+
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			if structField.Anonymous && fieldValue.Kind() == reflect.Struct {
+				instance.decodeStruct(key, fieldPath, fieldValue)
+			}
+			continue
+		}
+
+		instance.decodeValue(joinKey(key, tag), fieldPath, fieldValue, structField.Tag.Get(decoderDefaultTag))
+	}
+}
+
+// decodeValue decodes value located at key (or its children) into field
+func (instance *decoder) decodeValue(key string, field string, value reflect.Value, defaultValue string) {
+	raw, exists := instance.values[key]
+	children := instance.children(key)
+
+	if !exists && len(children) == 0 && defaultValue != "" {
+		raw, exists = []byte(defaultValue), true
+	}
+
+	if value.Kind() != reflect.Ptr && value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		if exists {
+			instance.check(key, field, value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(raw))
+		}
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !exists && len(children) == 0 {
+			return
+		}
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		instance.decodeValue(key, field, value.Elem(), defaultValue)
+	case reflect.Struct:
+		if exists && len(children) == 0 {
+			instance.check(key, field, json.Unmarshal(raw, value.Addr().Interface()))
+			return
+		}
+		instance.decodeStruct(key, field, value)
+	case reflect.Slice:
+		instance.decodeSlice(key, field, value, raw, exists, children)
+	case reflect.Map:
+		instance.decodeMap(key, field, value, raw, exists, children)
+	default:
+		if exists {
+			instance.check(key, field, decodeScalar(string(raw), value))
+		}
+	}
+}
+
+// decodeSlice decodes slice either from children keys or from JSON / comma-separated value
+func (instance *decoder) decodeSlice(key string, field string, value reflect.Value, raw []byte, exists bool, children []string) {
+	if value.Type().Elem().Kind() == reflect.Uint8 {
+		if exists {
+			value.SetBytes(raw)
+		}
+		return
+	}
+
+	if len(children) > 0 {
+		sortChildren(children)
+		slice := reflect.MakeSlice(value.Type(), len(children), len(children))
+		for index, child := range children {
+			instance.decodeValue(joinKey(key, child), fmt.Sprintf("%s[%d]", field, index), slice.Index(index), "")
+		}
+		value.Set(slice)
+		return
+	}
+
+	if !exists {
+		return
+	}
+
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, "[") {
+		instance.check(key, field, json.Unmarshal(raw, value.Addr().Interface()))
+		return
+	}
+
+	var parts []string
+	if trimmed != "" {
+		parts = strings.Split(trimmed, ",")
+	}
+	slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+	for index, part := range parts {
+		element := slice.Index(index)
+		if element.Kind() == reflect.Ptr {
+			element.Set(reflect.New(element.Type().Elem()))
+			element = element.Elem()
+		}
+		if err := decodeScalar(strings.TrimSpace(part), element); err != nil {
+			instance.check(key, fmt.Sprintf("%s[%d]", field, index), err)
+		}
+	}
+	value.Set(slice)
+}
+
+// decodeMap decodes map either from children keys or from JSON value
+func (instance *decoder) decodeMap(key string, field string, value reflect.Value, raw []byte, exists bool, children []string) {
+	mapType := value.Type()
+	if len(children) == 0 {
+		if exists {
+			instance.check(key, field, json.Unmarshal(raw, value.Addr().Interface()))
+		}
+		return
+	}
+
+	result := reflect.MakeMapWithSize(mapType, len(children))
+	for _, child := range children {
+		mapKey := reflect.New(mapType.Key()).Elem()
+		if err := decodeScalar(child, mapKey); err != nil {
+			instance.check(joinKey(key, child), field, err)
+			continue
+		}
+		element := reflect.New(mapType.Elem()).Elem()
+		instance.decodeValue(joinKey(key, child), fmt.Sprintf("%s[%s]", field, child), element, "")
+		result.SetMapIndex(mapKey, element)
+	}
+	value.Set(result)
+}
+
+// children returns unique names of direct children of the key
+func (instance *decoder) children(key string) []string {
+	prefix := key + "/"
+	unique := make(map[string]struct{})
+	for candidate := range instance.values {
+		if !strings.HasPrefix(candidate, prefix) {
+			continue
+		}
+		child := strings.TrimPrefix(candidate, prefix)
+		if index := strings.Index(child, "/"); index != -1 {
+			child = child[:index]
+		}
+		if child != "" {
+			unique[child] = struct{}{}
+		}
+	}
+
+	children := make([]string, 0, len(unique))
+	for child := range unique {
+		children = append(children, child)
+	}
+	sort.Strings(children)
+	return children
+}
+
+// check records decode error if it occurred
+func (instance *decoder) check(key string, field string, err error) {
+	if err != nil {
+		instance.errors = append(instance.errors, DecodeError{
+			Key:   key,
+			Field: field,
+			Err:   err,
+		})
+	}
+}
+
+// decodeScalar parses string into scalar value
+func decodeScalar(raw string, value reflect.Value) error {
+	raw = strings.TrimSpace(raw)
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	if value.CanAddr() && value.Addr().Type().Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(parsed)
+	case reflect.Interface:
+		if value.Type().NumMethod() != 0 {
+			return fmt.Errorf("unsupported type %s", value.Type())
+		}
+		value.Set(reflect.ValueOf(raw))
+	default:
+		return fmt.Errorf("unsupported type %s", value.Type())
+	}
+	return nil
+}
+
+// sortChildren sorts children numerically if all of them are numbers, lexicographically otherwise
+func sortChildren(children []string) {
+	numeric := true
+	for _, child := range children {
+		if _, err := strconv.Atoi(child); err != nil {
+			numeric = false
+			break
+		}
+	}
+	if !numeric {
+		sort.Strings(children)
+		return
+	}
+	sort.Slice(children, func(i, j int) bool {
+		left, _ := strconv.Atoi(children[i])
+		right, _ := strconv.Atoi(children[j])
+		return left < right
+	})
+}
+
+// joinKey joins parent key with child key
+func joinKey(parent string, child string) string {
+	child = strings.Trim(child, "/")
+	if parent == "" {
+		return child
+	}
+	return parent + "/" + child
+}
+
+// joinFieldPath joins parent field path with field name
+func joinFieldPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
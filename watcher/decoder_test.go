@@ -0,0 +1,148 @@
+package watcher
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+type decoderDatabase struct {
+	Host    string        `consul:"host" default:"localhost"`
+	Port    int           `consul:"port" default:"5432"`
+	Timeout time.Duration `consul:"timeout"`
+}
+
+type decoderConfig struct {
+	Name     string            `consul:"name"`
+	Enabled  bool              `consul:"enabled"`
+	Ratio    float64           `consul:"ratio"`
+	Retries  *uint             `consul:"retries"`
+	Tags     []string          `consul:"tags"`
+	Ports    []int             `consul:"ports"`
+	Labels   map[string]string `consul:"labels"`
+	Database decoderDatabase   `consul:"database"`
+	Extra    interface{}       `consul:"extra"`
+	Stringer fmt.Stringer      `consul:"stringer"`
+	Ignored  string            `consul:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	retries := uint(3)
+
+	tests := []struct {
+		name   string
+		prefix string
+		pairs  consulAPI.KVPairs
+		want   decoderConfig
+		errors []string
+	}{
+		{
+			name:  "defaults are used for missing keys",
+			pairs: consulAPI.KVPairs{},
+			want: decoderConfig{
+				Database: decoderDatabase{Host: "localhost", Port: 5432},
+			},
+		},
+		{
+			name:   "scalars are decoded relative to prefix",
+			prefix: "application",
+			pairs: consulAPI.KVPairs{
+				{Key: "application/name", Value: []byte("billing")},
+				{Key: "application/enabled", Value: []byte("true")},
+				{Key: "application/ratio", Value: []byte("0.5")},
+				{Key: "application/retries", Value: []byte("3")},
+				{Key: "application/database/host", Value: []byte("db.local")},
+				{Key: "application/database/timeout", Value: []byte("5s")},
+				{Key: "other/name", Value: []byte("ignored")},
+			},
+			want: decoderConfig{
+				Name:     "billing",
+				Enabled:  true,
+				Ratio:    0.5,
+				Retries:  &retries,
+				Database: decoderDatabase{Host: "db.local", Port: 5432, Timeout: 5 * time.Second},
+			},
+		},
+		{
+			name: "slices and maps are decoded from children, json and comma-separated values",
+			pairs: consulAPI.KVPairs{
+				{Key: "tags/1", Value: []byte("b")},
+				{Key: "tags/0", Value: []byte("a")},
+				{Key: "ports", Value: []byte("80, 443")},
+				{Key: "labels", Value: []byte(`{"team":"core"}`)},
+			},
+			want: decoderConfig{
+				Tags:     []string{"a", "b"},
+				Ports:    []int{80, 443},
+				Labels:   map[string]string{"team": "core"},
+				Database: decoderDatabase{Host: "localhost", Port: 5432},
+			},
+		},
+		{
+			name: "empty interface receives raw string",
+			pairs: consulAPI.KVPairs{
+				{Key: "extra", Value: []byte("value")},
+			},
+			want: decoderConfig{
+				Extra:    "value",
+				Database: decoderDatabase{Host: "localhost", Port: 5432},
+			},
+		},
+		{
+			name: "invalid values are reported as errors",
+			pairs: consulAPI.KVPairs{
+				{Key: "enabled", Value: []byte("maybe")},
+				{Key: "database/port", Value: []byte("port")},
+				{Key: "stringer", Value: []byte("value")},
+				{Key: "Ignored", Value: []byte("value")},
+			},
+			want: decoderConfig{
+				Database: decoderDatabase{Host: "localhost"},
+			},
+			errors: []string{"database/port", "enabled", "stringer"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config decoderConfig
+			errors := Decode(test.prefix, test.pairs, &config)
+
+			if !reflect.DeepEqual(config, test.want) {
+				t.Errorf("Decode() config = %+v, want %+v", config, test.want)
+			}
+
+			keys := make([]string, 0, len(errors))
+			for _, err := range errors {
+				keys = append(keys, err.Key)
+			}
+			sortChildren(keys)
+			if len(keys) != len(test.errors) || (len(keys) > 0 && !reflect.DeepEqual(keys, test.errors)) {
+				t.Errorf("Decode() errors for keys %v, want %v", keys, test.errors)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target interface{}
+	}{
+		{name: "nil", target: nil},
+		{name: "struct value", target: decoderConfig{}},
+		{name: "nil pointer", target: (*decoderConfig)(nil)},
+		{name: "pointer to non-struct", target: new(string)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if errors := Decode("", nil, test.target); len(errors) != 1 {
+				t.Errorf("Decode() returned %d errors, want 1", len(errors))
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"sort"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+// ChangeType represents type of change detected between two snapshots
+type ChangeType int
+
+const (
+	ChangeAdded ChangeType = iota
+	ChangeModified
+	ChangeDeleted
+)
+
+// String returns name of the change type
+func (changeType ChangeType) String() string {
+	switch changeType {
+	case ChangeAdded:
+		return "added"
+	case ChangeModified:
+		return "modified"
+	case ChangeDeleted:
+		return "deleted"
+	}
+	return "unknown"
+}
+
+// Change represents structure of single key change
+type Change struct {
+	Type ChangeType
+	Key  string
+	Old  *consulAPI.KVPair // Nil for added keys
+	New  *consulAPI.KVPair // Nil for deleted keys
+}
+
+// Diff compares two snapshots by key and ModifyIndex and returns list of changes sorted by key
+func Diff(previous consulAPI.KVPairs, current consulAPI.KVPairs) []Change {
+	previousByKey := make(map[string]*consulAPI.KVPair, len(previous))
+	for _, pair := range previous {
+		previousByKey[pair.Key] = pair
+	}
+
+	var changes []Change
+	for _, pair := range current {
+		old, exists := previousByKey[pair.Key]
+		delete(previousByKey, pair.Key)
+		switch {
+		case !exists:
+			changes = append(changes, Change{Type: ChangeAdded, Key: pair.Key, New: pair})
+		case old.ModifyIndex != pair.ModifyIndex:
+			changes = append(changes, Change{Type: ChangeModified, Key: pair.Key, Old: old, New: pair})
+		}
+	}
+
+	for key, pair := range previousByKey {
+		changes = append(changes, Change{Type: ChangeDeleted, Key: key, Old: pair})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
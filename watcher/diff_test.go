@@ -0,0 +1,92 @@
+package watcher
+
+import (
+	"reflect"
+	"testing"
+
+	consulAPI "github.com/hashicorp/consul/api"
+)
+
+func TestDiff(t *testing.T) {
+	first := &consulAPI.KVPair{Key: "a", ModifyIndex: 1}
+	second := &consulAPI.KVPair{Key: "b", ModifyIndex: 2}
+	secondModified := &consulAPI.KVPair{Key: "b", ModifyIndex: 5}
+	third := &consulAPI.KVPair{Key: "c", ModifyIndex: 3}
+
+	tests := []struct {
+		name     string
+		previous consulAPI.KVPairs
+		current  consulAPI.KVPairs
+		want     []Change
+	}{
+		{
+			name: "both snapshots are empty",
+		},
+		{
+			name:    "first snapshot is reported as added keys",
+			current: consulAPI.KVPairs{second, first},
+			want: []Change{
+				{Type: ChangeAdded, Key: "a", New: first},
+				{Type: ChangeAdded, Key: "b", New: second},
+			},
+		},
+		{
+			name:     "unchanged snapshot has no changes",
+			previous: consulAPI.KVPairs{first, second},
+			current:  consulAPI.KVPairs{first, second},
+		},
+		{
+			name:     "keys are compared by modify index",
+			previous: consulAPI.KVPairs{first, second},
+			current:  consulAPI.KVPairs{first, secondModified},
+			want: []Change{
+				{Type: ChangeModified, Key: "b", Old: second, New: secondModified},
+			},
+		},
+		{
+			name:     "added, modified and deleted keys are sorted by key",
+			previous: consulAPI.KVPairs{first, second},
+			current:  consulAPI.KVPairs{third, secondModified},
+			want: []Change{
+				{Type: ChangeDeleted, Key: "a", Old: first},
+				{Type: ChangeModified, Key: "b", Old: second, New: secondModified},
+				{Type: ChangeAdded, Key: "c", New: third},
+			},
+		},
+		{
+			name:     "all keys are deleted",
+			previous: consulAPI.KVPairs{first},
+			want: []Change{
+				{Type: ChangeDeleted, Key: "a", Old: first},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if changes := Diff(test.previous, test.current); !reflect.DeepEqual(changes, test.want) {
+				t.Errorf("Diff() = %+v, want %+v", changes, test.want)
+			}
+		})
+	}
+}
+
+func TestChangeTypeString(t *testing.T) {
+	tests := []struct {
+		changeType ChangeType
+		want       string
+	}{
+		{changeType: ChangeAdded, want: "added"},
+		{changeType: ChangeModified, want: "modified"},
+		{changeType: ChangeDeleted, want: "deleted"},
+		{changeType: ChangeType(42), want: "unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			if value := test.changeType.String(); value != test.want {
+				t.Errorf("String() = %s, want %s", value, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package watcher
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+	"github.com/leads-su/consul/metrics"
+)
+
+const (
+	defaultQuiescencePeriod  = 500 * time.Millisecond
+	defaultQuiescenceTimeout = 5 * time.Second
+	defaultWaitTime          = 30 * time.Minute
+)
+
+// blockingQuery performs single blocking query and returns its result together with index of the result
+type blockingQuery func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error)
+
+// emitter delivers result (received at index) to the consumer, returns false if watcher should stop
+type emitter func(result interface{}, index uint64, quitChannel <-chan struct{}) bool
+
+// queryResult represents structure of blocking query result together with its index
+type queryResult struct {
+	value interface{}
+	index uint64
+}
+
+// source represents structure of options shared by all watchers
+type source struct {
+	name              string
+	client            *consulAPI.Client
+	consulClient      *client.Client
+	errorChannel      chan<- error
+	quiescencePeriod  time.Duration
+	quiescenceTimeout time.Duration
+	logger            logging.Logger
+}
+
+// engine represents structure of blocking query loop shared by all watchers
+type engine struct {
+	sync.Mutex
+
+	quitChannel chan<- struct{}
+	doneChannel <-chan struct{}
+}
+
+// run executes blocking query loop, blocks until stop is called or context is cancelled
+func (engine *engine) run(ctx context.Context, source source, prepare func() error, query blockingQuery, emit emitter) error {
+	engine.Lock()
+
+	if engine.doneChannel != nil {
+		engine.Unlock()
+		return nil
+	}
+
+	quitChannel := make(chan struct{})
+	doneChannel := make(chan struct{})
+	engine.quitChannel = quitChannel
+	engine.doneChannel = doneChannel
+	engine.Unlock()
+
+	defer func() {
+		engine.Lock()
+		defer engine.Unlock()
+		close(doneChannel)
+		engine.doneChannel = nil
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			engine.stop()
+		case <-doneChannel:
+		}
+	}()
+
+	errorChannel, ok := source.errors()
+
+	if !ok {
+		defer close(errorChannel)
+	}
+
+	if prepare != nil {
+		if err := prepare(); err != nil {
+			errorChannel <- err
+			return err
+		}
+	}
+
+	qscPeriod := source.quiescencePeriod
+	qscTimeout := source.quiescenceTimeout
+
+	if qscPeriod == 0 {
+		qscPeriod = defaultQuiescencePeriod
+	}
+	if qscTimeout == 0 {
+		qscTimeout = defaultQuiescenceTimeout
+	}
+
+	resultsChannel := make(chan queryResult)
+
+	go func() {
+		var waitIndex uint64
+		for {
+			var result interface{}
+			var lastIndex uint64
+
+			queryOptions := &consulAPI.QueryOptions{
+				WaitIndex: waitIndex,
+				WaitTime:  defaultWaitTime,
+			}
+
+			err := backoff.Retry(func() error {
+				select {
+				case <-quitChannel:
+					return nil
+				default:
+				}
+
+				start := time.Now()
+				apiClient, err := source.apiClient()
+				if err == nil {
+					result, lastIndex, err = query(apiClient, queryOptions)
+				}
+				source.metrics().ObserveQuery(source.name, time.Since(start), err)
+
+				select {
+				case <-quitChannel:
+					return nil
+				default:
+				}
+
+				if err != nil {
+					source.log().Warnf("consul:watcher", "blocking query failed, retrying - %s", err.Error())
+					errorChannel <- err
+				}
+				return err
+			}, source.backOff())
+
+			if err != nil {
+				continue
+			}
+
+			select {
+			case <-quitChannel:
+				return
+			default:
+			}
+
+			if lastIndex == waitIndex {
+				continue
+			}
+			waitIndex = lastIndex
+
+			select {
+			case resultsChannel <- queryResult{value: result, index: lastIndex}:
+			case <-quitChannel:
+				return
+			}
+		}
+	}()
+
+	init := false
+	var result queryResult
+	var received time.Time
+	var qscPeriodChannel, qscTimeoutChannel <-chan time.Time
+
+	for {
+		select {
+		case <-quitChannel:
+			return nil
+		case result = <-resultsChannel:
+			qscPeriodChannel = time.After(qscPeriod)
+			if qscTimeoutChannel == nil {
+				qscTimeoutChannel = time.After(qscTimeout)
+				received = time.Now()
+			}
+			if init {
+				continue
+			}
+			init = true
+		case <-qscPeriodChannel:
+		case <-qscTimeoutChannel:
+		}
+
+		qscPeriodChannel = nil
+		qscTimeoutChannel = nil
+		source.metrics().ObserveQuiescence(source.name, time.Since(received))
+
+		if !emit(result.value, result.index, quitChannel) {
+			return nil
+		}
+		source.metrics().WatcherUpdated(source.name, result.index)
+	}
+}
+
+// stop stops blocking query loop and waits for it to finish
+func (engine *engine) stop() error {
+	engine.Lock()
+
+	if engine.doneChannel == nil {
+		engine.Unlock()
+		return nil
+	}
+
+	if engine.quitChannel != nil {
+		close(engine.quitChannel)
+		engine.quitChannel = nil
+	}
+
+	doneChannel := engine.doneChannel
+	engine.Unlock()
+	<-doneChannel
+	return nil
+}
+
+// errors returns error channel, creating discarding one if it is not specified
+func (source source) errors() (chan<- error, bool) {
+	errorChannel := source.errorChannel
+	ok := true
+
+	if errorChannel == nil {
+		ok = false
+		channel := make(chan error)
+		errorChannel = channel
+		go func() {
+			for range channel {
+			}
+		}()
+	}
+	return errorChannel, ok
+}
+
+// apiClient returns API client of managed Consul client (to survive failover) or the one passed directly,
+// or client.ErrNotConnected if there is no API client to query
+func (source source) apiClient() (*consulAPI.Client, error) {
+	if source.consulClient != nil {
+		return source.consulClient.APIClientE()
+	}
+	if source.client == nil {
+		return nil, client.ErrNotConnected
+	}
+	return source.client, nil
+}
+
+// metrics returns metrics of managed Consul client, or nil if metrics are not enabled
+func (source source) metrics() *metrics.Metrics {
+	if source.consulClient != nil {
+		return source.consulClient.Metrics()
+	}
+	return nil
+}
+
+// log returns logger specified in options, or logger of managed Consul client, with watcher name attached
+func (source source) log() logging.Logger {
+	logger := source.logger
+	if logger == nil && source.consulClient != nil {
+		logger = source.consulClient.Logger()
+	}
+	return logging.Or(logger).With("watcher", source.name)
+}
+
+// backOff returns back off policy used when query fails
+func (source source) backOff() backoff.BackOff {
+	result := backoff.NewExponentialBackOff()
+	result.InitialInterval = 1 * time.Second
+	result.MaxInterval = 10 * time.Second
+	result.MaxElapsedTime = 0
+	return result
+}
@@ -0,0 +1,85 @@
+package watcher
+
+import (
+	"context"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+)
+
+// EventsWatcher watches for user events
+type EventsWatcher struct {
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	Name              string                        // Name of the event to watch | Defaults to all events
+	UpdateChannel     chan<- []*consulAPI.UserEvent // Receives only events which were not delivered before
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+}
+
+// Start starts watching for events, blocks until Stop is called
+func (watcher *EventsWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for events, blocks until Stop is called or context is cancelled
+func (watcher *EventsWatcher) Run(ctx context.Context) error {
+	var lastEventID string
+
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		events, meta, err := apiClient.Event().List(watcher.Name, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return events, meta.LastIndex, nil
+	}
+
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		events := result.([]*consulAPI.UserEvent)
+		events = eventsAfter(events, lastEventID)
+		if len(events) == 0 {
+			return true
+		}
+		lastEventID = events[len(events)-1].ID
+
+		select {
+		case watcher.UpdateChannel <- events:
+			return true
+		case <-quitChannel:
+			return false
+		}
+	}
+
+	return watcher.run(ctx, source{
+		name:              "events:" + watcher.Name,
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
+	}, nil, query, emit)
+}
+
+// Stop stops watching for events
+func (watcher *EventsWatcher) Stop() error {
+	return watcher.stop()
+}
+
+// eventsAfter returns events which follow event with specified ID (all events if it is not found)
+func eventsAfter(events []*consulAPI.UserEvent, eventID string) []*consulAPI.UserEvent {
+	if eventID == "" {
+		return events
+	}
+	for index := len(events) - 1; index >= 0; index-- {
+		if events[index].ID == eventID {
+			return events[index+1:]
+		}
+	}
+	return events
+}
@@ -0,0 +1,138 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+)
+
+// ErrEmptyService is returned when service watcher is started without service name
+var ErrEmptyService = errors.New("service cannot be empty")
+
+// ServiceWatcher watches for changes in instances of the named service
+type ServiceWatcher struct {
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	Service           string
+	Tags              []string // Only instances having all tags are returned
+	DataCenter        string   // Datacenter to query | Defaults to datacenter of the agent
+	IncludeUnhealthy  bool     // Return all instances instead of passing ones only
+	UpdateChannel     chan<- []*consulAPI.ServiceEntry
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+}
+
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *ServiceWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *ServiceWatcher) Run(ctx context.Context) error {
+	prepare := func() error {
+		if watcher.Service == "" {
+			return ErrEmptyService
+		}
+		return nil
+	}
+
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		options.Datacenter = watcher.DataCenter
+		entries, meta, err := apiClient.Health().ServiceMultipleTags(watcher.Service, watcher.Tags, !watcher.IncludeUnhealthy, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return entries, meta.LastIndex, nil
+	}
+
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		select {
+		case watcher.UpdateChannel <- result.([]*consulAPI.ServiceEntry):
+			return true
+		case <-quitChannel:
+			return false
+		}
+	}
+
+	return watcher.run(ctx, source{
+		name:              "service:" + watcher.Service,
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
+	}, prepare, query, emit)
+}
+
+// Stop stops watching for changes
+func (watcher *ServiceWatcher) Stop() error {
+	return watcher.stop()
+}
+
+// ChecksWatcher watches for changes in health checks with the given state
+type ChecksWatcher struct {
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	State             string // One of consulAPI.HealthAny, HealthPassing, HealthWarning, HealthCritical | Defaults to HealthAny
+	UpdateChannel     chan<- consulAPI.HealthChecks
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+}
+
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *ChecksWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *ChecksWatcher) Run(ctx context.Context) error {
+	prepare := func() error {
+		if watcher.State == "" {
+			watcher.State = consulAPI.HealthAny
+		}
+		return nil
+	}
+
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		checks, meta, err := apiClient.Health().State(watcher.State, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return checks, meta.LastIndex, nil
+	}
+
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		select {
+		case watcher.UpdateChannel <- result.(consulAPI.HealthChecks):
+			return true
+		case <-quitChannel:
+			return false
+		}
+	}
+
+	return watcher.run(ctx, source{
+		name:              "checks:" + watcher.State,
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
+	}, prepare, query, emit)
+}
+
+// Stop stops watching for changes
+func (watcher *ChecksWatcher) Stop() error {
+	return watcher.stop()
+}
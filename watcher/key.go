@@ -0,0 +1,74 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+)
+
+// ErrEmptyKey is returned when key watcher is started without key
+var ErrEmptyKey = errors.New("key cannot be empty")
+
+// KeyWatcher watches for changes of single Consul KV key
+type KeyWatcher struct {
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	Key               string
+	UpdateChannel     chan<- *consulAPI.KVPair // Receives nil when key does not exist
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+}
+
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *KeyWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *KeyWatcher) Run(ctx context.Context) error {
+	prepare := func() error {
+		if watcher.Key == "" {
+			return ErrEmptyKey
+		}
+		return nil
+	}
+
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		pair, meta, err := apiClient.KV().Get(watcher.Key, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return pair, meta.LastIndex, nil
+	}
+
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		select {
+		case watcher.UpdateChannel <- result.(*consulAPI.KVPair):
+			return true
+		case <-quitChannel:
+			return false
+		}
+	}
+
+	return watcher.run(ctx, source{
+		name:              "key:" + watcher.Key,
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
+	}, prepare, query, emit)
+}
+
+// Stop stops watching for changes
+func (watcher *KeyWatcher) Stop() error {
+	return watcher.stop()
+}
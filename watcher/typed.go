@@ -0,0 +1,137 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+)
+
+// ErrInvalidConfig is returned when typed watcher configuration prototype is not a struct
+var ErrInvalidConfig = errors.New("config must be a struct or a pointer to struct")
+
+// TypedUpdate represents structure of decoded configuration update
+type TypedUpdate struct {
+	Config          interface{}   // Pointer to newly decoded configuration (same type as TypedWatcher.Config)
+	Errors          []DecodeError // Errors for keys which could not be decoded
+	ValidationError error         // Error returned by Validate method of configuration (if it implements Validator)
+	Index           uint64        // Blocking query index of the KV tree configuration was decoded from
+}
+
+// TypedWatcher watches for changes in Consul KV prefix and decodes them into struct
+type TypedWatcher struct {
+	sync.Mutex
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	Prefix            string
+	Config            interface{} // Prototype of configuration struct, e.g. &Config{}
+	UpdateChannel     chan<- TypedUpdate
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+
+	cancel      context.CancelFunc
+	doneChannel chan struct{}
+}
+
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *TypedWatcher) Start() {
+	watcher.Run(context.Background())
+}
+
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *TypedWatcher) Run(ctx context.Context) error {
+	configType := reflect.TypeOf(watcher.Config)
+	if configType != nil && configType.Kind() == reflect.Ptr {
+		configType = configType.Elem()
+	}
+	if configType == nil || configType.Kind() != reflect.Struct {
+		return ErrInvalidConfig
+	}
+
+	watcher.Lock()
+	if watcher.doneChannel != nil {
+		watcher.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	doneChannel := make(chan struct{})
+	watcher.cancel = cancel
+	watcher.doneChannel = doneChannel
+	watcher.Unlock()
+
+	defer func() {
+		watcher.Lock()
+		defer watcher.Unlock()
+		cancel()
+		close(doneChannel)
+		watcher.cancel = nil
+		watcher.doneChannel = nil
+	}()
+
+	pairsChannel := make(chan indexedPairs)
+	raw := &Watcher{
+		Client:            watcher.Client,
+		ConsulClient:      watcher.ConsulClient,
+		Prefix:            watcher.Prefix,
+		indexedChannel:    pairsChannel,
+		ErrorChannel:      watcher.ErrorChannel,
+		QuiescencePeriod:  watcher.QuiescencePeriod,
+		QuiescenceTimeout: watcher.QuiescenceTimeout,
+		Logger:            watcher.Logger,
+	}
+
+	resultChannel := make(chan error, 1)
+	go func() {
+		resultChannel <- raw.Run(ctx)
+	}()
+
+	for {
+		select {
+		case err := <-resultChannel:
+			return err
+		case pairs := <-pairsChannel:
+			update := watcher.decode(configType, pairs.pairs, pairs.index)
+			select {
+			case watcher.UpdateChannel <- update:
+			case err := <-resultChannel:
+				return err
+			}
+		}
+	}
+}
+
+// Stop stops watching for changes
+func (watcher *TypedWatcher) Stop() error {
+	watcher.Lock()
+	if watcher.doneChannel == nil {
+		watcher.Unlock()
+		return nil
+	}
+	watcher.cancel()
+	doneChannel := watcher.doneChannel
+	watcher.Unlock()
+	<-doneChannel
+	return nil
+}
+
+// decode decodes KV pairs received at given index into new instance of configuration
+func (watcher *TypedWatcher) decode(configType reflect.Type, pairs consulAPI.KVPairs, index uint64) TypedUpdate {
+	config := reflect.New(configType).Interface()
+	update := TypedUpdate{
+		Config: config,
+		Errors: Decode(watcher.Prefix, pairs, config),
+		Index:  index,
+	}
+
+	if validator, ok := config.(Validator); ok {
+		update.ValidationError = validator.Validate()
+	}
+	return update
+}
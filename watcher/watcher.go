@@ -1,191 +1,123 @@
 package watcher
 
 import (
+	"context"
 	"errors"
-	"github.com/cenkalti/backoff"
-	consulAPI "github.com/hashicorp/consul/api"
-	"sync"
 	"time"
+
+	consulAPI "github.com/hashicorp/consul/api"
+	"github.com/leads-su/consul/client"
+	"github.com/leads-su/consul/logging"
+	"github.com/leads-su/consul/state"
 )
 
+// ErrEmptyPrefix is returned when watcher is started without prefix
+var ErrEmptyPrefix = errors.New("prefix cannot be empty")
+
+// Watcher watches for changes in Consul KV prefix
 type Watcher struct {
-	sync.Mutex
-	Client				*consulAPI.Client
-	Prefix				string
-	UpdateChannel		chan <-consulAPI.KVPairs
-	ErrorChannel		chan <-error
-	QuiescencePeriod	time.Duration
-	QuiescenceTimeout	time.Duration
-
-	quitChannel			chan <-struct{}
-	doneChannel			<-chan struct{}
+	engine
+	Client            *consulAPI.Client
+	ConsulClient      *client.Client
+	Prefix            string
+	UpdateChannel     chan<- consulAPI.KVPairs
+	ChangesChannel    chan<- []Change
+	ErrorChannel      chan<- error
+	QuiescencePeriod  time.Duration
+	QuiescenceTimeout time.Duration
+	Logger            logging.Logger
+
+	indexedChannel chan<- indexedPairs
 }
 
-func (watcher *Watcher) Start () {
-	watcher.Lock()
-
-	if watcher.doneChannel != nil {
-		watcher.Unlock()
-		return
-	}
-
-	quitChannel := make(chan struct{})
-	doneChannel := make(chan struct{})
-	watcher.quitChannel = quitChannel
-	watcher.doneChannel = doneChannel
-	watcher.Unlock()
-
-	defer func() {
-		watcher.Lock()
-		defer watcher.Unlock()
-		close(doneChannel)
-		watcher.doneChannel = nil
-	}()
-
-	errorChannel, ok := watcher.errorChannel()
+// indexedPairs represents structure of KV pairs received by blocking query together with its index
+type indexedPairs struct {
+	pairs consulAPI.KVPairs
+	index uint64
+}
 
-	if !ok {
-		defer close(errorChannel)
-	}
+// Start starts watching for changes, blocks until Stop is called
+func (watcher *Watcher) Start() {
+	watcher.Run(context.Background())
+}
 
-	if watcher.Prefix == "" {
-		errorChannel <- errors.New("prefix cannot be empty")
-		return
-	}
+// Run starts watching for changes, blocks until Stop is called or context is cancelled
+func (watcher *Watcher) Run(ctx context.Context) error {
+	var previousPairs consulAPI.KVPairs
 
-	if watcher.Prefix[len(watcher.Prefix) - 1] != '/' {
-		watcher.Prefix  += "/"
+	prepare := func() error {
+		if watcher.Prefix == "" {
+			return ErrEmptyPrefix
+		}
+		if watcher.Prefix[len(watcher.Prefix)-1] != '/' {
+			watcher.Prefix += "/"
+		}
+		return nil
 	}
 
-	qscPeriod := watcher.QuiescencePeriod
-	qscTimeout := watcher.QuiescenceTimeout
-
-	if qscPeriod == 0 {
-		qscPeriod = 500 * time.Millisecond
-	}
-	if qscTimeout == 0 {
-		qscTimeout = 5 * time.Second
+	query := func(apiClient *consulAPI.Client, options *consulAPI.QueryOptions) (interface{}, uint64, error) {
+		pairs, meta, err := apiClient.KV().List(watcher.Prefix, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		return pairs, meta.LastIndex, nil
 	}
 
-	pairsChannel := make(chan consulAPI.KVPairs)
-
-	go func() {
-		var waitIndex uint64
-		for {
-			var pairs consulAPI.KVPairs
-			var meta *consulAPI.QueryMeta
+	emit := func(result interface{}, index uint64, quitChannel <-chan struct{}) bool {
+		pairs := result.(consulAPI.KVPairs)
 
-			queryOptions := &consulAPI.QueryOptions{
-				WaitIndex:	waitIndex,
-				WaitTime:	30 * time.Minute,
-			}
-
-			err := backoff.Retry(func() error {
-				select {
-				case <-quitChannel:
-					return nil
-				default:
-				}
-
-				var err error
-				pairs, meta, err = watcher.Client.KV().List(watcher.Prefix, queryOptions)
-
-				select {
-				case <-quitChannel:
-					return nil
-				default:
-				}
-
-				if err != nil {
-					errorChannel <- err
-				}
-				return err
-			}, watcher.backOff())
-
-			if err != nil {
-				continue
-			}
+		if watcher.ConsulClient != nil {
+			state.Publish(watcher.ConsulClient.Broker(), state.WatchUpdated{Prefix: watcher.Prefix, Index: index})
+		}
 
+		if watcher.UpdateChannel != nil {
 			select {
+			case watcher.UpdateChannel <- pairs:
 			case <-quitChannel:
-				return
-			default:
+				return false
 			}
+		}
 
-			if meta.LastIndex == waitIndex {
-				continue
+		if watcher.indexedChannel != nil {
+			select {
+			case watcher.indexedChannel <- indexedPairs{pairs: pairs, index: index}:
+			case <-quitChannel:
+				return false
 			}
-			waitIndex = meta.LastIndex
-			pairsChannel <- pairs
 		}
-	}()
-
-	init := false
-	var pairs consulAPI.KVPairs
-	var qscPeriodChannel, qscTimeoutChannel <-chan time.Time
-
-	for {
-		select {
-		case <-quitChannel:
-			return
-		case pairs = <-pairsChannel:
-			qscPeriodChannel = time.After(qscPeriod)
-			if qscTimeoutChannel == nil {
-				qscTimeoutChannel = time.After(qscTimeout)
+
+		if watcher.ChangesChannel != nil {
+			changes := Diff(previousPairs, pairs)
+			previousPairs = pairs
+			if len(changes) == 0 {
+				return true
 			}
-			if init {
-				continue
+			select {
+			case watcher.ChangesChannel <- changes:
+			case <-quitChannel:
+				return false
 			}
-			init = true
-		case <-qscPeriodChannel:
-		case <-qscTimeoutChannel:
 		}
-
-		qscPeriodChannel = nil
-		qscTimeoutChannel = nil
-
-		watcher.UpdateChannel <-pairs
+		return true
 	}
+
+	return watcher.run(ctx, watcher.source(), prepare, query, emit)
 }
 
+// Stop stops watching for changes
 func (watcher *Watcher) Stop() error {
-	watcher.Lock()
-
-	if watcher.doneChannel == nil {
-		watcher.Unlock()
-		return nil
-	}
-
-	if watcher.quitChannel != nil {
-		close(watcher.quitChannel)
-		watcher.quitChannel = nil
-	}
-
-	doneChannel := watcher.doneChannel
-	watcher.Unlock()
-	<-doneChannel
-	return nil
+	return watcher.stop()
 }
 
-func (watcher *Watcher) errorChannel() (chan <-error, bool) {
-	errorChannel := watcher.ErrorChannel
-	ok := true
-
-	if errorChannel == nil {
-		ok = false
-		channel := make(chan error)
-		errorChannel = channel
-		go func() {
-			for range channel {}
-		}()
+// source returns options shared by all watchers
+func (watcher *Watcher) source() source {
+	return source{
+		name:              "kv:" + watcher.Prefix,
+		client:            watcher.Client,
+		consulClient:      watcher.ConsulClient,
+		errorChannel:      watcher.ErrorChannel,
+		quiescencePeriod:  watcher.QuiescencePeriod,
+		quiescenceTimeout: watcher.QuiescenceTimeout,
+		logger:            watcher.Logger,
 	}
-	return errorChannel, ok
 }
-
-func (watcher *Watcher) backOff() backoff.BackOff {
-	result := backoff.NewExponentialBackOff()
-	result.InitialInterval = 1 * time.Second
-	result.MaxInterval = 10 * time.Second
-	result.MaxElapsedTime = 0
-	return result
-}
\ No newline at end of file